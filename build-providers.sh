@@ -22,9 +22,9 @@ PROVIDERS=(
 echo "Building providers: ${PROVIDERS[@]}"
 
 for provider in "${PROVIDERS[@]}"; do
-  if [[ -d "./internal/providers/$provider" ]]; then
+  if [[ -d "./cmd/providers/$provider" ]]; then
     echo "Building $provider..."
-    go build -buildmode=plugin -o ~/.config/elephant/providers/$provider.so ./internal/providers/$provider
+    go build -buildmode=plugin -o ~/.config/elephant/providers/$provider.so ./cmd/providers/$provider
     echo "✓ Built $provider.so"
   else
     echo "⚠ Provider $provider not found, skipping"
@@ -14,8 +14,11 @@ import (
 
 	"github.com/abenz1267/elephant/internal/comm"
 	"github.com/abenz1267/elephant/internal/comm/client"
+	"github.com/abenz1267/elephant/internal/comm/handlers"
 	"github.com/abenz1267/elephant/internal/common"
 	"github.com/abenz1267/elephant/internal/providers"
+	_ "github.com/abenz1267/elephant/internal/providers/builtin"
+	"github.com/abenz1267/elephant/internal/systemd"
 	"github.com/abenz1267/elephant/internal/util"
 	"github.com/joho/godotenv"
 	"github.com/urfave/cli/v3"
@@ -30,14 +33,20 @@ func main() {
 
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan,
-		syscall.SIGHUP,
 		syscall.SIGINT,
 		syscall.SIGTERM,
 		syscall.SIGQUIT, syscall.SIGUSR1)
 
 	go func() {
 		<-signalChan
-		os.Remove(comm.Socket)
+
+		// a second signal skips the graceful shutdown
+		go func() {
+			<-signalChan
+			os.Exit(1)
+		}()
+
+		shutdown()
 		os.Exit(0)
 	}()
 
@@ -66,18 +75,122 @@ func main() {
 					providers.Load()
 
 					for _, v := range providers.Providers {
-						if *v.Name == "menus" {
-							for _, m := range common.Menus {
+						if v.Name() == "menus" {
+							for _, m := range common.Menus() {
 								fmt.Printf("%s;menus:%s\n", m.NamePretty, m.Name)
 							}
 						} else {
-							fmt.Printf("%s;%s\n", *v.NamePretty, *v.Name)
+							fmt.Printf("%s;%s\n", v.NamePretty(), v.Name())
 						}
 					}
 
 					return nil
 				},
 			},
+			{
+				Name:  "hello",
+				Usage: "performs a protocol handshake with the running instance",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return client.Hello()
+				},
+			},
+			{
+				Name:  "reload",
+				Usage: "reloads configs, menus and providers of the running instance",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					return client.Reload()
+				},
+			},
+			{
+				Name:  "history",
+				Usage: "inspects and edits the history of the running instance",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:    "provider",
+						Aliases: []string{"p"},
+						Usage:   "only use the history of this provider, can be repeated. defaults to all providers",
+					},
+				},
+				Commands: []*cli.Command{
+					{
+						Name:  "list",
+						Usage: "lists usage per query as provider;query;identifier;amount;last used",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "query",
+								Usage: "only list entries of this query",
+							},
+							&cli.StringFlag{
+								Name:  "identifier",
+								Usage: "only list entries of this identifier",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							return client.HistoryList(cmd.StringSlice("provider"), cmd.String("query"), cmd.String("identifier"))
+						},
+					},
+					{
+						Name:  "export",
+						Usage: "exports the history as JSON to stdout or the given file",
+						Arguments: []cli.Argument{
+							&cli.StringArg{
+								Name: "file",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							return client.HistoryExport(cmd.StringSlice("provider"), cmd.StringArg("file"))
+						},
+					},
+					{
+						Name:  "import",
+						Usage: "merges an exported history from stdin or the given file",
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:  "replace",
+								Usage: "replace the current history instead of merging",
+							},
+						},
+						Arguments: []cli.Argument{
+							&cli.StringArg{
+								Name: "file",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							return client.HistoryImport(cmd.StringArg("file"), cmd.Bool("replace"))
+						},
+					},
+					{
+						Name:  "forget",
+						Usage: "removes an identifier from the history",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "query",
+								Usage: "only remove entries of this query",
+							},
+						},
+						Arguments: []cli.Argument{
+							&cli.StringArg{
+								Name: "identifier",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							return client.HistoryForget(cmd.StringSlice("provider"), cmd.String("query"), cmd.StringArg("identifier"))
+						},
+					},
+					{
+						Name:  "prune",
+						Usage: "removes entries not used within the given amount of days",
+						Arguments: []cli.Argument{
+							&cli.IntArg{
+								Name: "days",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							return client.HistoryPrune(cmd.StringSlice("provider"), int(cmd.IntArg("days")))
+						},
+					},
+				},
+			},
 			{
 				Name:    "menu",
 				Aliases: []string{"m"},
@@ -88,7 +201,69 @@ func main() {
 				},
 				Usage: "send request to open a menu",
 				Action: func(ctx context.Context, cmd *cli.Command) error {
-					client.RequestMenu(cmd.StringArg("menu"))
+					return client.RequestMenu(cmd.StringArg("menu"))
+				},
+			},
+			{
+				Name:  "validate",
+				Usage: "validates elephant.toml, menus and provider configs",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					logger := slog.New(slog.DiscardHandler)
+					slog.SetDefault(logger)
+
+					diags := providers.Validate()
+
+					for _, v := range diags {
+						fmt.Println(v)
+					}
+
+					if len(diags) > 0 {
+						return cli.Exit(fmt.Sprintf("found %d problem(s)", len(diags)), 1)
+					}
+
+					fmt.Println("no problems found")
+
+					return nil
+				},
+			},
+			{
+				Name:  "systemd",
+				Usage: "prints elephant.socket and elephant.service for starting elephant on the first connection",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "install",
+						Usage: "write the units to the systemd user unit directory instead of printing them",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					exe, err := os.Executable()
+					if err != nil {
+						return err
+					}
+
+					args := []string{}
+					if config != "" {
+						args = append(args, "--config", config)
+					}
+
+					socket, service := systemd.Units(exe, args, common.SocketPath())
+
+					if !cmd.Bool("install") {
+						fmt.Printf("# elephant.socket\n%s\n# elephant.service\n%s", socket, service)
+						return nil
+					}
+
+					files, err := systemd.Install(socket, service)
+					if err != nil {
+						return err
+					}
+
+					for _, v := range files {
+						fmt.Println("wrote", v)
+					}
+
+					fmt.Println("enable with: systemctl --user daemon-reload && systemctl --user enable --now elephant.socket")
+
 					return nil
 				},
 			},
@@ -100,8 +275,6 @@ func main() {
 					logger := slog.New(slog.DiscardHandler)
 					slog.SetDefault(logger)
 
-					providers.Load()
-
 					providers.Load()
 					util.GenerateDoc()
 					return nil
@@ -116,6 +289,18 @@ func main() {
 						DefaultText: "run async, close manually",
 						Usage:       "use to not close after querying, in case of async querying.",
 					},
+					&cli.BoolFlag{
+						Name:  "stream",
+						Usage: "receive results per provider as soon as they are ready.",
+					},
+					&cli.IntFlag{
+						Name:  "offset",
+						Usage: "skip the first results, used for paging.",
+					},
+					&cli.StringFlag{
+						Name:  "page",
+						Usage: "page token of a previous query to fetch the next page.",
+					},
 				},
 				Arguments: []cli.Argument{
 					&cli.StringArg{
@@ -123,9 +308,7 @@ func main() {
 					},
 				},
 				Action: func(ctx context.Context, cmd *cli.Command) error {
-					client.Query(cmd.StringArg("content"), cmd.Bool("async"))
-
-					return nil
+					return client.Query(cmd.StringArg("content"), cmd.Bool("async"), cmd.Bool("stream"), int(cmd.Int("offset")), cmd.String("page"))
 				},
 			},
 			{
@@ -136,9 +319,7 @@ func main() {
 					},
 				},
 				Action: func(ctx context.Context, cmd *cli.Command) error {
-					client.Activate(cmd.StringArg("content"))
-
-					return nil
+					return client.Activate(cmd.StringArg("content"))
 				},
 			},
 		},
@@ -164,6 +345,10 @@ func main() {
 		Action: func(context.Context, *cli.Command) error {
 			start := time.Now()
 
+			// SIGHUP terminates by default, catch it right away so reloads racing the startup don't kill the daemon
+			hup := make(chan os.Signal, 1)
+			signal.Notify(hup, syscall.SIGHUP)
+
 			if debug {
 				logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
 					Level: slog.LevelDebug,
@@ -171,17 +356,28 @@ func main() {
 				slog.SetDefault(logger)
 			}
 
+			if err := comm.Listen(); err != nil {
+				return err
+			}
+
 			loadLocalEnv()
 
 			common.InitRunPrefix()
 
 			providers.Load()
 
+			handlers.Version = version
+
+			go reloadOnSIGHUP(hup)
+
 			slog.Info("elephant", "startup", time.Since(start))
 
+			systemd.Ready()
+
 			comm.StartListen()
 
-			return nil
+			// listening only stops on shutdown, which exits once finished
+			select {}
 		},
 	}
 
@@ -190,6 +386,23 @@ func main() {
 	}
 }
 
+// shutdown stops accepting connections, cancels running queries and lets providers flush their data.
+func shutdown() {
+	slog.Info("elephant", "shutdown", "starting")
+
+	systemd.Stopping()
+	comm.Shutdown()
+	providers.Shutdown()
+}
+
+// reloadOnSIGHUP reloads once providers are loaded, a SIGHUP received during startup triggers a reload right away.
+func reloadOnSIGHUP(hup <-chan os.Signal) {
+	for range hup {
+		slog.Info("elephant", "reload", "SIGHUP")
+		providers.Reload()
+	}
+}
+
 func loadLocalEnv() {
 	envFile := filepath.Join(common.ConfigDir(), ".env")
 
@@ -0,0 +1,13 @@
+// Command calc builds the calc provider as a plugin:
+//
+//	go build -buildmode=plugin
+package main
+
+import (
+	"github.com/abenz1267/elephant/internal/providers/calc"
+	"github.com/abenz1267/elephant/pkg/provider"
+)
+
+var Provider provider.Provider = calc.Provider
+
+func main() {}
@@ -0,0 +1,13 @@
+// Command clipboard builds the clipboard provider as a plugin:
+//
+//	go build -buildmode=plugin
+package main
+
+import (
+	"github.com/abenz1267/elephant/internal/providers/clipboard"
+	"github.com/abenz1267/elephant/pkg/provider"
+)
+
+var Provider provider.Provider = clipboard.Provider
+
+func main() {}
@@ -0,0 +1,13 @@
+// Command desktopapplications builds the desktopapplications provider as a plugin:
+//
+//	go build -buildmode=plugin
+package main
+
+import (
+	"github.com/abenz1267/elephant/internal/providers/desktopapplications"
+	"github.com/abenz1267/elephant/pkg/provider"
+)
+
+var Provider provider.Provider = desktopapplications.Provider
+
+func main() {}
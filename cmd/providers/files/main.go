@@ -0,0 +1,13 @@
+// Command files builds the files provider as a plugin:
+//
+//	go build -buildmode=plugin
+package main
+
+import (
+	"github.com/abenz1267/elephant/internal/providers/files"
+	"github.com/abenz1267/elephant/pkg/provider"
+)
+
+var Provider provider.Provider = files.Provider
+
+func main() {}
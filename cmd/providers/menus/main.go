@@ -0,0 +1,13 @@
+// Command menus builds the menus provider as a plugin:
+//
+//	go build -buildmode=plugin
+package main
+
+import (
+	"github.com/abenz1267/elephant/internal/providers/menus"
+	"github.com/abenz1267/elephant/pkg/provider"
+)
+
+var Provider provider.Provider = menus.Provider
+
+func main() {}
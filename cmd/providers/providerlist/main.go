@@ -0,0 +1,13 @@
+// Command providerlist builds the providerlist provider as a plugin:
+//
+//	go build -buildmode=plugin
+package main
+
+import (
+	"github.com/abenz1267/elephant/internal/providers/providerlist"
+	"github.com/abenz1267/elephant/pkg/provider"
+)
+
+var Provider provider.Provider = providerlist.Provider
+
+func main() {}
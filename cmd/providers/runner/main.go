@@ -0,0 +1,13 @@
+// Command runner builds the runner provider as a plugin:
+//
+//	go build -buildmode=plugin
+package main
+
+import (
+	"github.com/abenz1267/elephant/internal/providers/runner"
+	"github.com/abenz1267/elephant/pkg/provider"
+)
+
+var Provider provider.Provider = runner.Provider
+
+func main() {}
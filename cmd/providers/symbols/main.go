@@ -0,0 +1,13 @@
+// Command symbols builds the symbols provider as a plugin:
+//
+//	go build -buildmode=plugin
+package main
+
+import (
+	"github.com/abenz1267/elephant/internal/providers/symbols"
+	"github.com/abenz1267/elephant/pkg/provider"
+)
+
+var Provider provider.Provider = symbols.Provider
+
+func main() {}
@@ -0,0 +1,13 @@
+// Command websearch builds the websearch provider as a plugin:
+//
+//	go build -buildmode=plugin
+package main
+
+import (
+	"github.com/abenz1267/elephant/internal/providers/websearch"
+	"github.com/abenz1267/elephant/pkg/provider"
+)
+
+var Provider provider.Provider = websearch.Provider
+
+func main() {}
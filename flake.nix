@@ -44,7 +44,7 @@
 
         src = ./.;
 
-        vendorHash = "sha256-MQ97Z+xOdjYfcV+XxpXP5n7ep87rWVAZgx+EK6KIiVg=";
+        vendorHash = "sha256-UtxYi3CJfZ21RGfv7bfdnabWbO6qDvw9kmJo9EpYfqI=";
 
         buildInputs = with pkgs; [
           protobuf
@@ -71,6 +71,30 @@
         };
       };
 
+      # Single binary with all bundled providers compiled in, no plugins needed
+      elephant-builtin = pkgs.buildGoModule {
+        pname = "elephant-builtin";
+        version = "0.1.0";
+
+        src = ./.;
+
+        vendorHash = "sha256-UtxYi3CJfZ21RGfv7bfdnabWbO6qDvw9kmJo9EpYfqI=";
+
+        subPackages = ["cmd"];
+        tags = ["builtin"];
+
+        postInstall = ''
+          mv $out/bin/cmd $out/bin/elephant
+        '';
+
+        meta = with lib; {
+          description = "Elephant with all bundled providers compiled into the binary";
+          homepage = "https://github.com/abenz1267/elephant";
+          license = licenses.gpl3Only;
+          platforms = platforms.linux;
+        };
+      };
+
       # Providers package - builds all providers with same Go toolchain
       elephant-providers = pkgs.buildGoModule {
         pname = "elephant-providers";
@@ -78,7 +102,7 @@
 
         src = ./.;
 
-        vendorHash = "sha256-MQ97Z+xOdjYfcV+XxpXP5n7ep87rWVAZgx+EK6KIiVg=";
+        vendorHash = "sha256-UtxYi3CJfZ21RGfv7bfdnabWbO6qDvw9kmJo9EpYfqI=";
 
         nativeBuildInputs = with pkgs; [
           protobuf
@@ -105,9 +129,9 @@
           echo "Building elephant providers..."
 
           for provider in "''${PROVIDERS[@]}"; do
-            if [[ -d "./internal/providers/$provider" ]]; then
+            if [[ -d "./cmd/providers/$provider" ]]; then
               echo "Building $provider provider..."
-              go build -buildmode=plugin -o "$provider.so" ./internal/providers/$provider
+              go build -buildmode=plugin -o "$provider.so" ./cmd/providers/$provider
               echo "✓ Built $provider.so"
             else
               echo "⚠ Provider $provider not found, skipping"
@@ -4,24 +4,25 @@ go 1.24.5
 
 require (
 	github.com/adrg/xdg v0.5.3
+	github.com/djherbis/times v1.6.0
 	github.com/fsnotify/fsnotify v1.9.0
+	github.com/pelletier/go-toml/v2 v2.2.4
 	google.golang.org/protobuf v1.36.7
 )
 
 require (
-	github.com/djherbis/times v1.6.0 // indirect
 	github.com/fatih/structs v1.1.0 // indirect
 	github.com/go-viper/mapstructure/v2 v2.3.0 // indirect
 	github.com/knadh/koanf/maps v0.1.2 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/mitchellh/copystructure v1.2.0 // indirect
 	github.com/mitchellh/reflectwalk v1.0.2 // indirect
-	github.com/pelletier/go-toml/v2 v2.2.4 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
 )
 
 require (
 	github.com/charlievieth/fastwalk v1.0.12
+	github.com/jezek/xgb v1.1.1
 	github.com/joho/godotenv v1.5.1
 	github.com/junegunn/fzf v0.65.0
 	github.com/knadh/koanf/parsers/toml/v2 v2.2.0
@@ -29,5 +30,5 @@ require (
 	github.com/knadh/koanf/providers/structs v1.0.0
 	github.com/knadh/koanf/v2 v2.2.2
 	github.com/urfave/cli/v3 v3.3.8
-	golang.org/x/sys v0.32.0 // indirect
+	golang.org/x/sys v0.32.0
 )
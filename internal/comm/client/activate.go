@@ -2,17 +2,21 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/binary"
+	"fmt"
+	"io"
 	"net"
 	"strconv"
 	"strings"
 
+	"github.com/abenz1267/elephant/internal/common"
 	"github.com/abenz1267/elephant/pkg/pb/pb"
 	"google.golang.org/protobuf/proto"
 )
 
-func Activate(data string) {
+func Activate(data string) error {
 	v := strings.Split(data, ";")
 	qid, _ := strconv.Atoi(v[0])
 
@@ -29,12 +33,19 @@ func Activate(data string) {
 		panic(err)
 	}
 
-	conn, err := net.Dial("unix", socket)
+	conn, err := net.Dial("unix", common.SocketPath())
 	if err != nil {
 		panic(err)
 	}
 	defer conn.Close()
 
+	// responses are only sent after negotiating the protocol version
+	reader := bufio.NewReader(conn)
+
+	if _, err := handshake(conn, reader); err != nil {
+		return err
+	}
+
 	var buffer bytes.Buffer
 	buffer.Write([]byte{1})
 
@@ -47,4 +58,26 @@ func Activate(data string) {
 	if err != nil {
 		panic(err)
 	}
+
+	t, payload, err := readFrame(reader)
+	if err != nil {
+		if err == io.EOF {
+			return nil
+		}
+
+		return err
+	}
+
+	if t == statusError {
+		return toError(payload)
+	}
+
+	resp := &pb.ActivateResponse{}
+	if err := proto.Unmarshal(payload, resp); err != nil {
+		return err
+	}
+
+	fmt.Println(resp)
+
+	return nil
 }
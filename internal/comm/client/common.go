@@ -1,13 +1,63 @@
 package client
 
 import (
-	"os"
-	"path/filepath"
-)
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
 
-var socket = filepath.Join(os.TempDir(), "elephant.sock")
+	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"google.golang.org/protobuf/proto"
+)
 
 const (
-	done  = 255
-	empty = 254
+	queryBatch  = 2
+	queryCommit = 3
+	done        = 255
+	empty       = 254
+	statusError = 253
 )
+
+// Error is an error reported by elephant. It implements cli.ExitCoder, so returning it from a command
+// exits with a non-zero exit code derived from the error code.
+type Error struct {
+	Code    pb.ErrorResponse_Code
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// ExitCode is the error code offset by one, so unknown errors also exit non-zero.
+func (e *Error) ExitCode() int {
+	return int(e.Code) + 1
+}
+
+func readFrame(reader *bufio.Reader) (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return 0, nil, err
+	}
+
+	length := binary.BigEndian.Uint32(header[1:5])
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return 0, nil, err
+	}
+
+	return header[0], payload, nil
+}
+
+func toError(payload []byte) error {
+	resp := &pb.ErrorResponse{}
+	if err := proto.Unmarshal(payload, resp); err != nil {
+		return err
+	}
+
+	return &Error{
+		Code:    resp.Code,
+		Message: resp.Message,
+	}
+}
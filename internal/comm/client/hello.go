@@ -0,0 +1,73 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/abenz1267/elephant/internal/comm/handlers"
+	"github.com/abenz1267/elephant/internal/common"
+	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+func Hello() error {
+	conn, err := net.Dial("unix", common.SocketPath())
+	if err != nil {
+		panic(err)
+	}
+	defer conn.Close()
+
+	resp, err := handshake(conn, bufio.NewReader(conn))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(resp)
+
+	return nil
+}
+
+// handshake negotiates the protocol version for the connection.
+func handshake(conn net.Conn, reader *bufio.Reader) (*pb.HelloResponse, error) {
+	req := pb.HelloRequest{
+		ProtocolVersion: handlers.ProtocolVersion,
+		Client:          "elephant",
+	}
+
+	b, err := proto.Marshal(&req)
+	if err != nil {
+		panic(err)
+	}
+
+	var buffer bytes.Buffer
+	buffer.Write([]byte{4})
+
+	lengthBuf := make([]byte, 4)
+	binary.BigEndian.PutUint32(lengthBuf, uint32(len(b)))
+	buffer.Write(lengthBuf)
+	buffer.Write(b)
+
+	_, err = conn.Write(buffer.Bytes())
+	if err != nil {
+		panic(err)
+	}
+
+	t, payload, err := readFrame(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if t == statusError {
+		return nil, toError(payload)
+	}
+
+	resp := &pb.HelloResponse{}
+	if err := proto.Unmarshal(payload, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,166 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/abenz1267/elephant/internal/common"
+	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// HistoryList prints all history entries as provider;query;identifier;amount;last used.
+func HistoryList(providers []string, query, identifier string) error {
+	resp, err := history(&pb.HistoryRequest{
+		Command:    pb.HistoryRequest_LIST,
+		Providers:  providers,
+		Query:      query,
+		Identifier: identifier,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, v := range resp.Entries {
+		fmt.Printf("%s;%s;%s;%d;%s\n", v.Provider, v.Query, v.Identifier, v.Amount, time.Unix(v.LastUsed, 0).Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// HistoryExport writes the histories as JSON to file, stdout if file is empty or "-".
+func HistoryExport(providers []string, file string) error {
+	resp, err := history(&pb.HistoryRequest{
+		Command:   pb.HistoryRequest_EXPORT,
+		Providers: providers,
+	})
+	if err != nil {
+		return err
+	}
+
+	if file == "" || file == "-" {
+		_, err := fmt.Println(string(resp.Data))
+		return err
+	}
+
+	return os.WriteFile(file, resp.Data, 0o600)
+}
+
+// HistoryImport merges the exported histories in file, stdin if file is empty or "-", into the current ones.
+func HistoryImport(file string, replace bool) error {
+	var b []byte
+	var err error
+
+	if file == "" || file == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(file)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := history(&pb.HistoryRequest{
+		Command: pb.HistoryRequest_IMPORT,
+		Data:    b,
+		Replace: replace,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, v := range resp.Skipped {
+		fmt.Printf("skipped '%s', provider has no history\n", v)
+	}
+
+	fmt.Printf("imported %d entries\n", resp.Affected)
+
+	return nil
+}
+
+// HistoryForget removes the identifier from the histories.
+func HistoryForget(providers []string, query, identifier string) error {
+	resp, err := history(&pb.HistoryRequest{
+		Command:    pb.HistoryRequest_FORGET,
+		Providers:  providers,
+		Query:      query,
+		Identifier: identifier,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("removed %d entries\n", resp.Affected)
+
+	return nil
+}
+
+// HistoryPrune removes entries not used within the given amount of days.
+func HistoryPrune(providers []string, days int) error {
+	if days <= 0 {
+		return errors.New("days has to be greater than 0")
+	}
+
+	resp, err := history(&pb.HistoryRequest{
+		Command:   pb.HistoryRequest_PRUNE,
+		Providers: providers,
+		Days:      uint32(days),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("removed %d entries\n", resp.Affected)
+
+	return nil
+}
+
+func history(req *pb.HistoryRequest) (*pb.HistoryResponse, error) {
+	b, err := proto.Marshal(req)
+	if err != nil {
+		panic(err)
+	}
+
+	conn, err := net.Dial("unix", common.SocketPath())
+	if err != nil {
+		panic(err)
+	}
+	defer conn.Close()
+
+	var buffer bytes.Buffer
+	buffer.Write([]byte{7})
+
+	lengthBuf := make([]byte, 4)
+	binary.BigEndian.PutUint32(lengthBuf, uint32(len(b)))
+	buffer.Write(lengthBuf)
+	buffer.Write(b)
+
+	_, err = conn.Write(buffer.Bytes())
+	if err != nil {
+		panic(err)
+	}
+
+	t, payload, err := readFrame(bufio.NewReader(conn))
+	if err != nil {
+		return nil, err
+	}
+
+	if t == statusError {
+		return nil, toError(payload)
+	}
+
+	resp := &pb.HistoryResponse{}
+	if err := proto.Unmarshal(payload, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
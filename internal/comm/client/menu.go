@@ -1,15 +1,18 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/binary"
+	"io"
 	"net"
 
+	"github.com/abenz1267/elephant/internal/common"
 	"github.com/abenz1267/elephant/pkg/pb/pb"
 	"google.golang.org/protobuf/proto"
 )
 
-func RequestMenu(menu string) {
+func RequestMenu(menu string) error {
 	req := pb.MenuRequest{
 		Menu: menu,
 	}
@@ -19,12 +22,19 @@ func RequestMenu(menu string) {
 		panic(err)
 	}
 
-	conn, err := net.Dial("unix", socket)
+	conn, err := net.Dial("unix", common.SocketPath())
 	if err != nil {
 		panic(err)
 	}
 	defer conn.Close()
 
+	// responses are only sent after negotiating the protocol version
+	reader := bufio.NewReader(conn)
+
+	if _, err := handshake(conn, reader); err != nil {
+		return err
+	}
+
 	var buffer bytes.Buffer
 	buffer.Write([]byte{3})
 
@@ -37,4 +47,19 @@ func RequestMenu(menu string) {
 	if err != nil {
 		panic(err)
 	}
+
+	t, payload, err := readFrame(reader)
+	if err != nil {
+		if err == io.EOF {
+			return nil
+		}
+
+		return err
+	}
+
+	if t == statusError {
+		return toError(payload)
+	}
+
+	return nil
 }
@@ -11,11 +11,13 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/abenz1267/elephant/internal/common"
 	"github.com/abenz1267/elephant/pkg/pb/pb"
 	"google.golang.org/protobuf/proto"
 )
 
-func Query(data string, async bool) {
+// Query sends a query and prints the responses. Offset and the page token of a previous response select further pages.
+func Query(data string, async, stream bool, offset int, page string) error {
 	v := strings.Split(data, ";")
 	maxresults, _ := strconv.Atoi(v[2])
 
@@ -23,6 +25,9 @@ func Query(data string, async bool) {
 		Providers:  strings.Split(v[0], ","),
 		Query:      v[1],
 		Maxresults: int32(maxresults),
+		Stream:     stream,
+		Offset:     int32(offset),
+		PageToken:  page,
 	}
 
 	b, err := proto.Marshal(&req)
@@ -30,12 +35,21 @@ func Query(data string, async bool) {
 		panic(err)
 	}
 
-	conn, err := net.Dial("unix", socket)
+	conn, err := net.Dial("unix", common.SocketPath())
 	if err != nil {
 		panic(err)
 	}
 	defer conn.Close()
 
+	reader := bufio.NewReader(conn)
+
+	// streamed frames are only sent to clients negotiating a protocol version supporting them
+	if stream {
+		if _, err := handshake(conn, reader); err != nil {
+			return err
+		}
+	}
+
 	var buffer bytes.Buffer
 	buffer.Write([]byte{0})
 
@@ -49,7 +63,7 @@ func Query(data string, async bool) {
 		panic(err)
 	}
 
-	reader := bufio.NewReader(conn)
+	var queryErr error
 
 	for {
 		header, err := reader.Peek(5)
@@ -64,7 +78,7 @@ func Query(data string, async bool) {
 			break
 		}
 
-		if header[0] != 0 && header[0] != 1 && header[0] != done && header[0] != empty {
+		if header[0] > queryCommit && header[0] != done && header[0] != empty && header[0] != statusError {
 			panic("invalid protocol prefix")
 		}
 
@@ -78,11 +92,28 @@ func Query(data string, async bool) {
 
 		payload := msg[5:]
 
-		resp := &pb.QueryResponse{}
+		if header[0] == statusError {
+			queryErr = toError(payload)
+			continue
+		}
+
+		var resp proto.Message
+
+		switch header[0] {
+		case queryBatch:
+			resp = &pb.QueryBatchResponse{}
+		case queryCommit:
+			resp = &pb.QueryCommitResponse{}
+		default:
+			resp = &pb.QueryResponse{}
+		}
+
 		if err := proto.Unmarshal(payload, resp); err != nil {
 			panic(err)
 		}
 
 		fmt.Println(resp)
 	}
+
+	return queryErr
 }
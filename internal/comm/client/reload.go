@@ -0,0 +1,51 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"net"
+
+	"github.com/abenz1267/elephant/internal/common"
+	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+func Reload() error {
+	req := pb.ReloadRequest{}
+
+	b, err := proto.Marshal(&req)
+	if err != nil {
+		panic(err)
+	}
+
+	conn, err := net.Dial("unix", common.SocketPath())
+	if err != nil {
+		panic(err)
+	}
+	defer conn.Close()
+
+	var buffer bytes.Buffer
+	buffer.Write([]byte{5})
+
+	lengthBuf := make([]byte, 4)
+	binary.BigEndian.PutUint32(lengthBuf, uint32(len(b)))
+	buffer.Write(lengthBuf)
+	buffer.Write(b)
+
+	_, err = conn.Write(buffer.Bytes())
+	if err != nil {
+		panic(err)
+	}
+
+	t, payload, err := readFrame(bufio.NewReader(conn))
+	if err != nil {
+		return err
+	}
+
+	if t == statusError {
+		return toError(payload)
+	}
+
+	return nil
+}
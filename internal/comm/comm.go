@@ -3,19 +3,27 @@ package comm
 
 import (
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net"
 	"os"
 	"path/filepath"
+	"syscall"
 
 	"github.com/abenz1267/elephant/internal/comm/handlers"
+	"github.com/abenz1267/elephant/internal/common"
+	"github.com/abenz1267/elephant/internal/systemd"
+	"github.com/abenz1267/elephant/pkg/pb/pb"
 )
 
 // connection id
 var (
-	cid    uint32
-	Socket = filepath.Join(os.TempDir(), "elephant.sock")
+	cid uint32
+	// Socket is the path of the socket, set once listening
+	Socket   string
+	listener *net.UnixListener
 )
 
 var registry []MessageHandler
@@ -29,6 +37,10 @@ const (
 	ActivateRequestHandlerPos  = 1
 	SubscribeRequestHandlerPos = 2
 	MenuRequestHandlerPos      = 3
+	HelloRequestHandlerPos     = 4
+	ReloadRequestHandlerPos    = 5
+	CancelRequestHandlerPos    = 6
+	HistoryRequestHandlerPos   = 7
 )
 
 func init() {
@@ -38,25 +50,79 @@ func init() {
 	registry[ActivateRequestHandlerPos] = &handlers.ActivateRequest{}
 	registry[SubscribeRequestHandlerPos] = &handlers.SubscribeRequest{}
 	registry[MenuRequestHandlerPos] = &handlers.MenuRequest{}
+	registry[HelloRequestHandlerPos] = &handlers.HelloRequest{}
+	registry[ReloadRequestHandlerPos] = &handlers.ReloadRequest{}
+	registry[CancelRequestHandlerPos] = &handlers.CancelRequest{}
+	registry[HistoryRequestHandlerPos] = &handlers.HistoryRequest{}
 }
 
-func StartListen() {
-	os.Remove(Socket)
+// Listen uses the socket passed by systemd or creates one, only accessible by the current user. It fails if another
+// instance is already listening on it, stale sockets of crashed instances are removed.
+func Listen() error {
+	l, err := systemd.Listener()
+	if err != nil {
+		return err
+	}
+
+	if l != nil {
+		slog.Info("comm", "socket", "systemd")
+		listener = l
+
+		return nil
+	}
+
+	path := common.SocketPath()
 
-	l, err := net.ListenUnix("unix", &net.UnixAddr{
-		Name: Socket,
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return fmt.Errorf("elephant is already running on %s", path)
+	}
+
+	os.Remove(path)
+
+	l, err = net.ListenUnix("unix", &net.UnixAddr{
+		Name: path,
 	})
 	if err != nil {
-		slog.Error("comm", "socket", err)
+		return err
+	}
+
+	// the umask is process wide, so it can't be used to create the socket with these permissions. Connections of other
+	// users are rejected anyways.
+	if err := os.Chmod(path, 0o600); err != nil {
+		l.Close()
+		return err
 	}
-	defer l.Close()
 
-	slog.Info("comm", "listen", "starting")
+	Socket = path
+	listener = l
+
+	return nil
+}
+
+func StartListen() {
+	defer listener.Close()
+
+	slog.Info("comm", "listen", listener.Addr())
 
 	for {
-		conn, err := l.AcceptUnix()
+		conn, err := listener.AcceptUnix()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+
 		if err != nil {
 			slog.Error("comm", "accept", err)
+			continue
+		}
+
+		if !sameUser(conn) {
+			conn.Close()
+			continue
 		}
 
 		slog.Info("comm", "connection", "new")
@@ -67,18 +133,58 @@ func StartListen() {
 	}
 }
 
+// Shutdown stops accepting connections and cancels all running queries.
+func Shutdown() {
+	if listener != nil {
+		listener.Close()
+	}
+
+	handlers.CancelQueries()
+}
+
+// sameUser checks via SO_PEERCRED that the peer runs as the same user as elephant.
+func sameUser(conn *net.UnixConn) bool {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		slog.Error("comm", "peercred", err)
+		return false
+	}
+
+	var cred *syscall.Ucred
+	var credErr error
+
+	err = raw.Control(func(fd uintptr) {
+		cred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err == nil {
+		err = credErr
+	}
+
+	if err != nil {
+		slog.Error("comm", "peercred", err)
+		return false
+	}
+
+	if cred.Uid != uint32(os.Getuid()) {
+		slog.Warn("comm", "connection", "rejected", "uid", cred.Uid, "pid", cred.Pid)
+		return false
+	}
+
+	return true
+}
+
 func handle(conn net.Conn, cid uint32) {
 	defer conn.Close()
+	defer handlers.Disconnected(cid)
 
 	for {
 		tb := make([]byte, 1)
 		if _, err := io.ReadFull(conn, tb); err != nil {
-			if err == io.EOF {
-				break
+			if err != io.EOF {
+				slog.Error("conn", "readtype", err)
 			}
 
-			slog.Error("conn", "readtype", err)
-			continue
+			break
 		}
 
 		mType := int(tb[0])
@@ -97,6 +203,21 @@ func handle(conn net.Conn, cid uint32) {
 			continue
 		}
 
+		if registry[mType] == nil {
+			slog.Error("conn", "type", mType, "error", "unknown request type")
+			handlers.WriteError(cid, pb.ErrorResponse_UNKNOWN_REQUEST_TYPE, fmt.Sprintf("unknown request type %d", mType), conn)
+			continue
+		}
+
+		// queries run concurrently, so newer queries and cancel requests can abort them. They are registered before
+		// reading the next request, so cancellations always apply to the queries sent before.
+		if q, ok := registry[mType].(*handlers.QueryRequest); ok {
+			run := q.Start(cid, conn, p)
+			go run()
+
+			continue
+		}
+
 		registry[mType].Handle(cid, conn, p)
 	}
 }
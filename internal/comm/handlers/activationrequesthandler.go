@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"log/slog"
 	"net"
 	"strings"
@@ -10,12 +11,15 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+const ActivationDone = 252
+
 type ActivateRequest struct{}
 
 func (a *ActivateRequest) Handle(cid uint32, conn net.Conn, data []byte) {
 	req := &pb.ActivateRequest{}
 	if err := proto.Unmarshal(data, req); err != nil {
 		slog.Error("activationrequesthandler", "protobuf", err)
+		WriteError(cid, pb.ErrorResponse_INVALID_REQUEST, err.Error(), conn)
 
 		return
 	}
@@ -26,7 +30,37 @@ func (a *ActivateRequest) Handle(cid uint32, conn net.Conn, data []byte) {
 		provider = strings.Split(provider, ":")[0]
 	}
 
-	providers.Providers[provider].Activate(uint32(req.Qid), req.Identifier, req.Action, req.Arguments)
+	p, ok := providers.Providers[provider]
+	if !ok {
+		slog.Error("activationrequesthandler", "provider", req.Provider, "error", "unknown provider")
+		WriteError(cid, pb.ErrorResponse_UNKNOWN_PROVIDER, fmt.Sprintf("unknown provider '%s'", req.Provider), conn)
+
+		return
+	}
+
+	err := p.Activate(uint32(req.Qid), req.Identifier, req.Action, req.Arguments)
 
 	providers.Cleanup(uint32(req.Qid))
+
+	if err != nil {
+		slog.Error("activationrequesthandler", "provider", req.Provider, "identifier", req.Identifier, "error", err)
+		WriteError(cid, errorCode(err), err.Error(), conn)
+
+		return
+	}
+
+	if !responds(cid) {
+		return
+	}
+
+	resp := &pb.ActivateResponse{
+		Qid:        req.Qid,
+		Provider:   req.Provider,
+		Identifier: req.Identifier,
+		Action:     req.Action,
+	}
+
+	if err := writeMessage(ActivationDone, resp, conn); err != nil {
+		slog.Error("activationrequesthandler", "write", err)
+	}
 }
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"sync"
+
+	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+type inflight struct {
+	cancel context.CancelFunc
+}
+
+var (
+	inflights   = make(map[uint32]*inflight)
+	inflightsMu sync.Mutex
+)
+
+// CancelRequest cancels the in-flight query of the connection. There is no response, the cancelled query stops sending items.
+type CancelRequest struct{}
+
+func (a *CancelRequest) Handle(cid uint32, conn net.Conn, data []byte) {
+	req := &pb.CancelRequest{}
+	if err := proto.Unmarshal(data, req); err != nil {
+		slog.Error("cancelrequesthandler", "protobuf", err)
+		writeError(pb.ErrorResponse_INVALID_REQUEST, err.Error(), conn)
+
+		return
+	}
+
+	CancelQuery(cid)
+}
+
+// CancelQuery cancels the in-flight query of the connection, if any.
+func CancelQuery(cid uint32) {
+	inflightsMu.Lock()
+	defer inflightsMu.Unlock()
+
+	if q, ok := inflights[cid]; ok {
+		slog.Info("queryhandler", "cancel", cid)
+		q.cancel()
+		delete(inflights, cid)
+	}
+}
+
+// CancelQueries cancels the in-flight queries of all connections.
+func CancelQueries() {
+	inflightsMu.Lock()
+	defer inflightsMu.Unlock()
+
+	for k, v := range inflights {
+		v.cancel()
+		delete(inflights, k)
+	}
+}
+
+// startQuery cancels the in-flight query of the connection and returns the context for the new one.
+// The context outlives the request, so async items can still be delivered until a newer query or
+// cancel arrives or the client disconnects.
+func startQuery(cid uint32) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	inflightsMu.Lock()
+	defer inflightsMu.Unlock()
+
+	if prev, ok := inflights[cid]; ok {
+		prev.cancel()
+	}
+
+	inflights[cid] = &inflight{cancel: cancel}
+
+	return ctx
+}
@@ -2,9 +2,19 @@ package handlers
 
 import (
 	"bytes"
+	"encoding/binary"
+	"errors"
+	"log/slog"
 	"net"
+
+	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"github.com/abenz1267/elephant/pkg/provider"
+	"google.golang.org/protobuf/proto"
 )
 
+// StatusError is used for every request type to signal an error, the payload is a pb.ErrorResponse.
+const StatusError = 253
+
 func writeStatus(status int, conn net.Conn) (bool, error) {
 	var buffer bytes.Buffer
 	buffer.Write([]byte{byte(status)})
@@ -19,3 +29,57 @@ func writeStatus(status int, conn net.Conn) (bool, error) {
 
 	return true, nil
 }
+
+func writeMessage(status int, msg proto.Message, conn net.Conn) error {
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	buffer.Write([]byte{byte(status)})
+
+	lengthBuf := make([]byte, 4)
+	binary.BigEndian.PutUint32(lengthBuf, uint32(len(b)))
+	buffer.Write(lengthBuf)
+	buffer.Write(b)
+
+	_, err = conn.Write(buffer.Bytes())
+
+	return err
+}
+
+// WriteError sends an error response for request types of protocol version 1, which clients only expect after
+// negotiating ResponseProtocolVersion.
+func WriteError(cid uint32, code pb.ErrorResponse_Code, message string, conn net.Conn) {
+	if !responds(cid) {
+		return
+	}
+
+	writeError(code, message, conn)
+}
+
+// writeError sends an error response unconditionally, for request types only sent by clients knowing error frames.
+func writeError(code pb.ErrorResponse_Code, message string, conn net.Conn) {
+	resp := &pb.ErrorResponse{
+		Code:    code,
+		Message: message,
+	}
+
+	if err := writeMessage(StatusError, resp, conn); err != nil {
+		slog.Error("handlers", "writeerror", err)
+	}
+}
+
+func errorCode(err error) pb.ErrorResponse_Code {
+	switch {
+	case errors.Is(err, provider.ErrUnknownIdentifier):
+		return pb.ErrorResponse_UNKNOWN_IDENTIFIER
+	case errors.Is(err, provider.ErrActionNotSupported):
+		return pb.ErrorResponse_ACTION_NOT_SUPPORTED
+	case errors.Is(err, provider.ErrCommandFailed):
+		return pb.ErrorResponse_COMMAND_FAILED
+	default:
+		return pb.ErrorResponse_UNKNOWN
+	}
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/abenz1267/elephant/internal/common"
+	"github.com/abenz1267/elephant/internal/providers"
+	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ProtocolVersion is the version of the socket protocol spoken by this daemon.
+	ProtocolVersion = 2
+	// MinProtocolVersion is the oldest protocol version still supported. Clients not sending a hello are assumed to speak this version.
+	MinProtocolVersion = 1
+	// StreamProtocolVersion is the first protocol version with streamed batch and commit frames.
+	StreamProtocolVersion = 2
+	// ResponseProtocolVersion is the first protocol version answering activate and menu requests and sending error
+	// frames for the request types of version 1.
+	ResponseProtocolVersion = 2
+)
+
+const HelloDone = 0
+
+var (
+	// Version is the daemon version reported to clients.
+	Version string
+
+	protocolsMut sync.Mutex
+	protocols    = make(map[uint32]int32)
+)
+
+type HelloRequest struct{}
+
+func (a *HelloRequest) Handle(cid uint32, conn net.Conn, data []byte) {
+	req := &pb.HelloRequest{}
+	if err := proto.Unmarshal(data, req); err != nil {
+		slog.Error("hellorequesthandler", "protobuf", err)
+		writeError(pb.ErrorResponse_INVALID_REQUEST, err.Error(), conn)
+
+		return
+	}
+
+	if req.ProtocolVersion < MinProtocolVersion {
+		slog.Error("hellorequesthandler", "client", req.Client, "protocol", req.ProtocolVersion, "error", "incompatible")
+		writeError(pb.ErrorResponse_INCOMPATIBLE_PROTOCOL, fmt.Sprintf("protocol version %d not supported, minimum is %d", req.ProtocolVersion, MinProtocolVersion), conn)
+		conn.Close()
+
+		return
+	}
+
+	negotiated := min(req.ProtocolVersion, ProtocolVersion)
+
+	protocolsMut.Lock()
+	protocols[cid] = negotiated
+	protocolsMut.Unlock()
+
+	slog.Info("hellorequesthandler", "client", req.Client, "protocol", negotiated)
+
+	resp := &pb.HelloResponse{
+		ProtocolVersion:    negotiated,
+		MinProtocolVersion: MinProtocolVersion,
+		Version:            strings.TrimSpace(Version),
+		Providers:          providerInfo(),
+	}
+
+	if err := writeMessage(HelloDone, resp, conn); err != nil {
+		slog.Error("hellorequesthandler", "write", err)
+	}
+}
+
+// Protocol returns the negotiated protocol version for a connection.
+func Protocol(cid uint32) int32 {
+	protocolsMut.Lock()
+	defer protocolsMut.Unlock()
+
+	if v, ok := protocols[cid]; ok {
+		return v
+	}
+
+	return MinProtocolVersion
+}
+
+// responds reports if the connection expects responses and errors for the request types of protocol version 1.
+func responds(cid uint32) bool {
+	return Protocol(cid) >= ResponseProtocolVersion
+}
+
+// Disconnected cleans up connection specific data.
+func Disconnected(cid uint32) {
+	protocolsMut.Lock()
+	delete(protocols, cid)
+	protocolsMut.Unlock()
+
+	CancelQuery(cid)
+}
+
+func providerInfo() []*pb.HelloResponse_Provider {
+	res := []*pb.HelloResponse_Provider{}
+
+	for _, v := range providers.Providers {
+		if v.Name() == "menus" {
+			for _, m := range common.Menus() {
+				res = append(res, &pb.HelloResponse_Provider{
+					Name:       fmt.Sprintf("%s:%s", "menus", m.Name),
+					NamePretty: m.NamePretty,
+				})
+			}
+
+			continue
+		}
+
+		res = append(res, &pb.HelloResponse_Provider{
+			Name:       v.Name(),
+			NamePretty: v.NamePretty(),
+			Actions:    providers.Actions(v),
+		})
+	}
+
+	slices.SortFunc(res, func(a, b *pb.HelloResponse_Provider) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return res
+}
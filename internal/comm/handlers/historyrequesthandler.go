@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/abenz1267/elephant/internal/common/history"
+	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const HistoryDone = 0
+
+type HistoryRequest struct{}
+
+func (a *HistoryRequest) Handle(cid uint32, conn net.Conn, data []byte) {
+	req := &pb.HistoryRequest{}
+	if err := proto.Unmarshal(data, req); err != nil {
+		slog.Error("historyrequesthandler", "protobuf", err)
+		writeError(pb.ErrorResponse_INVALID_REQUEST, err.Error(), conn)
+
+		return
+	}
+
+	resp := &pb.HistoryResponse{}
+
+	if req.Command == pb.HistoryRequest_IMPORT {
+		affected, skipped, err := history.ImportJSON(req.Data, req.Replace)
+		if err != nil {
+			writeError(pb.ErrorResponse_INVALID_REQUEST, err.Error(), conn)
+			return
+		}
+
+		resp.Affected = int32(affected)
+		resp.Skipped = skipped
+
+		slog.Info("historyrequesthandler", "import", affected, "skipped", skipped)
+
+		if err := writeMessage(HistoryDone, resp, conn); err != nil {
+			slog.Error("historyrequesthandler", "write", err)
+		}
+
+		return
+	}
+
+	providers := req.Providers
+	if len(providers) == 0 {
+		providers = history.Providers()
+	}
+
+	histories := []*history.History{}
+
+	for _, v := range providers {
+		h, ok := history.Get(v)
+		if !ok {
+			writeError(pb.ErrorResponse_UNKNOWN_PROVIDER, fmt.Sprintf("no history for provider '%s'", v), conn)
+			return
+		}
+
+		histories = append(histories, h)
+	}
+
+	switch req.Command {
+	case pb.HistoryRequest_LIST:
+		for _, h := range histories {
+			for _, e := range h.Entries() {
+				if (req.Query != "" && e.Query != req.Query) || (req.Identifier != "" && e.Identifier != req.Identifier) {
+					continue
+				}
+
+				resp.Entries = append(resp.Entries, &pb.HistoryResponse_Entry{
+					Provider:   h.Provider,
+					Query:      e.Query,
+					Identifier: e.Identifier,
+					Amount:     int32(e.Amount),
+					LastUsed:   e.LastUsed.Unix(),
+				})
+			}
+		}
+	case pb.HistoryRequest_EXPORT:
+		b, err := history.ExportJSON(providers)
+		if err != nil {
+			writeError(pb.ErrorResponse_COMMAND_FAILED, err.Error(), conn)
+			return
+		}
+
+		resp.Data = b
+	case pb.HistoryRequest_FORGET:
+		if req.Identifier == "" {
+			writeError(pb.ErrorResponse_INVALID_REQUEST, "identifier is required", conn)
+			return
+		}
+
+		for _, h := range histories {
+			resp.Affected += int32(h.Forget(req.Identifier, req.Query))
+		}
+
+		slog.Info("historyrequesthandler", "forget", req.Identifier, "removed", resp.Affected)
+	case pb.HistoryRequest_PRUNE:
+		if req.Days == 0 {
+			writeError(pb.ErrorResponse_INVALID_REQUEST, "days has to be greater than 0", conn)
+			return
+		}
+
+		before := time.Now().AddDate(0, 0, -int(req.Days))
+
+		for _, h := range histories {
+			resp.Affected += int32(h.Prune(before))
+		}
+
+		slog.Info("historyrequesthandler", "prune", req.Days, "removed", resp.Affected)
+	default:
+		writeError(pb.ErrorResponse_INVALID_REQUEST, fmt.Sprintf("unknown command %d", req.Command), conn)
+		return
+	}
+
+	if err := writeMessage(HistoryDone, resp, conn); err != nil {
+		slog.Error("historyrequesthandler", "write", err)
+	}
+}
@@ -5,19 +5,33 @@ import (
 	"log/slog"
 	"net"
 
+	"github.com/abenz1267/elephant/internal/common"
 	"github.com/abenz1267/elephant/pkg/pb/pb"
 	"google.golang.org/protobuf/proto"
 )
 
+const MenuOpened = 251
+
 type MenuRequest struct{}
 
 func (a *MenuRequest) Handle(cid uint32, conn net.Conn, data []byte) {
 	req := &pb.MenuRequest{}
 	if err := proto.Unmarshal(data, req); err != nil {
 		slog.Error("menurequesthandler", "protobuf", err)
+		WriteError(cid, pb.ErrorResponse_INVALID_REQUEST, err.Error(), conn)
+
+		return
+	}
+
+	if _, ok := common.Menus()[req.Menu]; !ok {
+		WriteError(cid, pb.ErrorResponse_UNKNOWN_PROVIDER, fmt.Sprintf("unknown menu '%s'", req.Menu), conn)
 
 		return
 	}
 
 	ProviderUpdated <- fmt.Sprintf("%s:%s", "menus", req.Menu)
+
+	if responds(cid) {
+		writeStatus(MenuOpened, conn)
+	}
 }
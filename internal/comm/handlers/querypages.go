@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/abenz1267/elephant/pkg/pb/pb"
+)
+
+// pageTTL is how long sorted results are kept for paging after they were last accessed.
+const pageTTL = 60 * time.Second
+
+// resultSet is the sorted result of a query iteration, used to serve further pages without querying providers again.
+type resultSet struct {
+	cid       uint32
+	qid       uint32
+	iid       uint32
+	query     string
+	providers []string
+	exact     bool
+	entries   []*pb.QueryResponse_Item
+	accessed  time.Time
+}
+
+var (
+	resultSets   = make(map[uint32]*resultSet)
+	resultSetsMu sync.Mutex
+)
+
+// storeResults caches the sorted entries of the query, replacing older iterations of it.
+func storeResults(cid, qid, iid uint32, req *pb.QueryRequest, entries []*pb.QueryResponse_Item) *resultSet {
+	set := &resultSet{
+		cid:       cid,
+		qid:       qid,
+		iid:       iid,
+		query:     req.Query,
+		providers: req.Providers,
+		exact:     req.Exactsearch,
+		entries:   entries,
+		accessed:  time.Now(),
+	}
+
+	resultSetsMu.Lock()
+	defer resultSetsMu.Unlock()
+
+	for k, v := range resultSets {
+		if time.Since(v.accessed) > pageTTL {
+			delete(resultSets, k)
+		}
+	}
+
+	resultSets[qid] = set
+
+	return set
+}
+
+// findResults returns the cached results and offset for the requested page. A page token has to
+// match the latest iteration of its query, a plain offset the last identical query of the connection.
+// Returns nil if there are no matching results for the offset, so the query is run again.
+func findResults(cid uint32, req *pb.QueryRequest) (*resultSet, int, error) {
+	resultSetsMu.Lock()
+	defer resultSetsMu.Unlock()
+
+	if req.PageToken != "" {
+		qid, iid, offset, err := parsePageToken(req.PageToken)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		set, ok := resultSets[qid]
+		if !ok || set.iid != iid {
+			return nil, 0, fmt.Errorf("page token '%s' expired", req.PageToken)
+		}
+
+		set.accessed = time.Now()
+
+		return set, offset, nil
+	}
+
+	var found *resultSet
+
+	for _, v := range resultSets {
+		if v.cid == cid && v.query == req.Query && v.exact == req.Exactsearch && slices.Equal(v.providers, req.Providers) {
+			if found == nil || v.qid > found.qid {
+				found = v
+			}
+		}
+	}
+
+	if found == nil {
+		return nil, 0, nil
+	}
+
+	found.accessed = time.Now()
+
+	return found, int(req.Offset), nil
+}
+
+// page returns up to limit entries starting at offset and the token for the following page, if there is one.
+// There are no pages without a positive limit.
+func (s *resultSet) page(offset, limit int) ([]*pb.QueryResponse_Item, string) {
+	if offset < 0 || offset >= len(s.entries) || limit <= 0 {
+		return nil, ""
+	}
+
+	end := min(offset+limit, len(s.entries))
+
+	next := ""
+	if end < len(s.entries) {
+		next = pageToken(s.qid, s.iid, end)
+	}
+
+	return s.entries[offset:end], next
+}
+
+func pageToken(qid, iid uint32, offset int) string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%d:%d:%d", qid, iid, offset))
+}
+
+func parsePageToken(token string) (qid, iid uint32, offset int, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		_, err = fmt.Sscanf(string(b), "%d:%d:%d", &qid, &iid, &offset)
+	}
+
+	if err != nil || offset < 0 {
+		return 0, 0, 0, fmt.Errorf("invalid page token '%s'", token)
+	}
+
+	return qid, iid, offset, nil
+}
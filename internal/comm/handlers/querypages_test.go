@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"strconv"
+	"testing"
+
+	"github.com/abenz1267/elephant/pkg/pb/pb"
+)
+
+func testSet(n int) *resultSet {
+	s := &resultSet{qid: 1, iid: 2}
+
+	for i := range n {
+		s.entries = append(s.entries, &pb.QueryResponse_Item{Identifier: strconv.Itoa(i)})
+	}
+
+	return s
+}
+
+func TestPage(t *testing.T) {
+	s := testSet(5)
+
+	tests := []struct {
+		offset, limit int
+		want          int
+		next          bool
+	}{
+		{0, 2, 2, true},
+		{2, 2, 2, true},
+		{4, 2, 1, false},
+		{5, 2, 0, false},
+		{-1, 2, 0, false},
+		{0, 0, 0, false},
+		{0, -1, 0, false},
+	}
+
+	for _, tt := range tests {
+		page, next := s.page(tt.offset, tt.limit)
+
+		if len(page) != tt.want || (next != "") != tt.next {
+			t.Errorf("offset %d, limit %d: got %d entries, next %q", tt.offset, tt.limit, len(page), next)
+		}
+	}
+}
+
+func TestPageToken(t *testing.T) {
+	s := testSet(5)
+
+	_, next := s.page(0, 2)
+
+	qid, iid, offset, err := parsePageToken(next)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if qid != 1 || iid != 2 || offset != 2 {
+		t.Errorf("got qid %d, iid %d, offset %d", qid, iid, offset)
+	}
+
+	for _, v := range []string{"invalid", base64.RawURLEncoding.EncodeToString([]byte("1:2:-1"))} {
+		if _, _, _, err := parsePageToken(v); err == nil {
+			t.Errorf("%q: expected an error", v)
+		}
+	}
+}
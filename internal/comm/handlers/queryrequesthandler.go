@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"fmt"
 	"log/slog"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/abenz1267/elephant/internal/providers"
 	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"github.com/abenz1267/elephant/pkg/provider"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -22,6 +24,8 @@ const (
 	QueryNoResults = 254
 	QueryItem      = 0
 	QueryAsyncItem = 1
+	QueryBatch     = 2
+	QueryCommit    = 3
 )
 
 type queryData struct {
@@ -31,13 +35,24 @@ type queryData struct {
 }
 
 var (
-	qid                              atomic.Uint32
-	queries                          = make(map[uint32]map[uint32]*queryData)
-	queryMutex                       sync.Mutex
-	MaxGlobalItemsToDisplayWebsearch = 0
-	WebsearchPrefixes                = make(map[string]string)
+	qid        atomic.Uint32
+	queries    = make(map[uint32]map[uint32]*queryData)
+	queryMutex sync.Mutex
+
+	websearchMu                      sync.RWMutex
+	maxGlobalItemsToDisplayWebsearch = 0
+	websearchPrefixes                = make(map[string]string)
 )
 
+// SetWebsearch sets the prefixes of the websearch entries, mapped to the entry name, and how many results are
+// allowed when querying multiple providers before the default websearch entry is hidden.
+func SetWebsearch(prefixes map[string]string, maxGlobalItems int) {
+	websearchMu.Lock()
+	websearchPrefixes = prefixes
+	maxGlobalItemsToDisplayWebsearch = maxGlobalItems
+	websearchMu.Unlock()
+}
+
 func handleAsync(qid, iid uint32, conn net.Conn) {
 	for item := range providers.AsyncChannels[qid][iid] {
 		req := pb.QueryResponse{
@@ -69,19 +84,80 @@ func handleAsync(qid, iid uint32, conn net.Conn) {
 type QueryRequest struct{}
 
 func (h *QueryRequest) Handle(cid uint32, conn net.Conn, data []byte) {
+	h.Start(cid, conn, data)()
+}
+
+// Start parses the request and registers the query for the connection, cancelling the running one. The returned
+// function sends the results and can run concurrently. Start itself has to be called in the order requests arrive, so
+// newer queries and cancel requests always abort the older query.
+func (h *QueryRequest) Start(cid uint32, conn net.Conn, data []byte) func() {
 	start := time.Now()
 
 	req := &pb.QueryRequest{}
 	if err := proto.Unmarshal(data, req); err != nil {
 		slog.Error("queryhandler", "protobuf", err)
+		WriteError(cid, pb.ErrorResponse_INVALID_REQUEST, err.Error(), conn)
+		writeStatus(QueryDone, conn)
 
-		return
+		return func() {}
+	}
+
+	if req.Offset < 0 {
+		WriteError(cid, pb.ErrorResponse_INVALID_REQUEST, fmt.Sprintf("invalid offset %d", req.Offset), conn)
+		writeStatus(QueryDone, conn)
+
+		return func() {}
+	}
+
+	// further pages are served from the cached results, without querying the providers again
+	if req.PageToken != "" || req.Offset > 0 {
+		set, offset, err := findResults(cid, req)
+		if err != nil {
+			WriteError(cid, pb.ErrorResponse_INVALID_REQUEST, err.Error(), conn)
+			writeStatus(QueryDone, conn)
+
+			return func() {}
+		}
+
+		if set != nil {
+			return func() {
+				page, next := set.page(offset, int(req.Maxresults))
+				writeResults(set.qid, set.iid, page, next, func() bool { return false }, conn)
+				slog.Info("providers", "page", offset, "qid", set.qid, "iid", set.iid, "results", len(page), "time", time.Since(start))
+			}
+		}
+	}
+
+	ctx := startQuery(cid)
+
+	return func() {
+		runQuery(ctx, cid, req, start, conn)
+	}
+}
+
+// runQuery asks the providers and sends the results, unless ctx got cancelled in the meantime.
+func runQuery(ctx context.Context, cid uint32, req *pb.QueryRequest, start time.Time, conn net.Conn) {
+	// clients not knowing batch and commit frames get the merged results
+	stream := req.Stream && Protocol(cid) >= StreamProtocolVersion
+
+	for _, v := range req.Providers {
+		if strings.HasPrefix(v, "menus:") {
+			v = strings.Split(v, ":")[0]
+		}
+
+		if _, ok := providers.Providers[v]; !ok {
+			WriteError(cid, pb.ErrorResponse_UNKNOWN_PROVIDER, fmt.Sprintf("unknown provider '%s'", v), conn)
+		}
 	}
 
 	wsprefix := ""
 
+	websearchMu.RLock()
+	prefixes, maxWebsearch := websearchPrefixes, maxGlobalItemsToDisplayWebsearch
+	websearchMu.RUnlock()
+
 	if slices.Contains(req.Providers, "websearch") {
-		for k, v := range WebsearchPrefixes {
+		for k, v := range prefixes {
 			if strings.HasPrefix(req.Query, k) {
 				wsprefix = v
 			}
@@ -96,31 +172,38 @@ func (h *QueryRequest) Handle(cid uint32, conn net.Conn, data []byte) {
 
 	var currentQID uint32
 	var currentIteration uint32
+	var current *queryData
 
 	if req.Query != "" {
 		lastLength := 0
 
+		queryMutex.Lock()
 		for k, v := range queries[cid] {
 			if strings.HasPrefix(req.Query, v.Query) && len(v.Query) > lastLength {
 				currentQID = k
+				current = v
 				lastLength = len(v.Query)
-				v.Iteration.Add(1)
-				currentIteration = v.Iteration.Load()
 			}
 		}
 
+		if current != nil {
+			current.Iteration.Add(1)
+			currentIteration = current.Iteration.Load()
+		}
+		queryMutex.Unlock()
+
 		if currentQID == 0 {
 			qid.Add(1)
 			currentQID = qid.Load()
 
 			queryMutex.Lock()
 			providers.QueryProviders[currentQID] = req.Providers
-			data := &queryData{
+			current = &queryData{
 				Query: req.Query,
 			}
-			data.Iteration.Add(1)
-			currentIteration = data.Iteration.Load()
-			queries[cid][currentQID] = data
+			current.Iteration.Add(1)
+			currentIteration = current.Iteration.Load()
+			queries[cid][currentQID] = current
 			queryMutex.Unlock()
 
 			slog.Info("providers", "query", "new", "qid", currentQID, "iid", currentIteration, "text", req.Query)
@@ -139,19 +222,29 @@ func (h *QueryRequest) Handle(cid uint32, conn net.Conn, data []byte) {
 	var wg sync.WaitGroup
 	wg.Add(len(req.Providers))
 
+	providers.Timestampedqueries.Lock()
 	providers.Timestampedqueries.Data[currentQID] = time.Now()
+	providers.Timestampedqueries.Unlock()
 
 	entries := []*pb.QueryResponse_Item{}
 
+	queryMutex.Lock()
 	if _, ok := providers.AsyncChannels[currentQID]; !ok {
 		providers.AsyncChannels[currentQID] = make(map[uint32]chan *pb.QueryResponse_Item)
 	}
 
 	providers.AsyncChannels[currentQID][currentIteration] = make(chan *pb.QueryResponse_Item)
+	queryMutex.Unlock()
 
 	go handleAsync(currentQID, currentIteration, conn)
 
+	// stale reports if the query got cancelled or superseded by a newer iteration
+	stale := func() bool {
+		return ctx.Err() != nil || req.Query != "" && currentIteration != current.Iteration.Load()
+	}
+
 	for _, v := range req.Providers {
+		name := v
 		query := req.Query
 
 		if strings.HasPrefix(v, "menus:") {
@@ -163,71 +256,166 @@ func (h *QueryRequest) Handle(cid uint32, conn net.Conn, data []byte) {
 		go func(text string, wg *sync.WaitGroup) {
 			defer wg.Done()
 			if p, ok := providers.Providers[v]; ok {
-				res := p.Query(currentQID, currentIteration, text, len(req.Providers) == 1, req.Exactsearch)
+				res := p.Query(ctx, currentQID, currentIteration, text, len(req.Providers) == 1, req.Exactsearch)
 
 				mut.Lock()
 				entries = append(entries, res...)
 				mut.Unlock()
+
+				if stream && !stale() {
+					writeBatch(currentQID, currentIteration, name, res, int(req.Maxresults), conn)
+				}
 			}
 		}(query, &wg)
 	}
 
-	wg.Wait()
+	waited := make(chan struct{})
 
-	slices.SortFunc(entries, sortEntries)
+	go func() {
+		wg.Wait()
+		close(waited)
+	}()
 
-	if len(entries) == 0 {
-		writeStatus(QueryNoResults, conn)
-		writeStatus(QueryDone, conn)
-		slog.Info("providers", "results", len(entries), "time", time.Since(start))
+	// don't wait for providers ignoring the cancellation
+	select {
+	case <-waited:
+	case <-ctx.Done():
+		slog.Info("providers", "results", "cancelled", "qid", currentQID, "iid", currentIteration)
 		return
 	}
 
-	if len(entries) > int(req.Maxresults) {
-		entries = entries[:req.Maxresults]
-	}
+	slices.SortFunc(entries, sortEntries)
 
-	hideWebsearch := len(req.Providers) > 1 && len(entries) > MaxGlobalItemsToDisplayWebsearch
+	hideWebsearch := len(req.Providers) > 1 && min(len(entries), int(req.Maxresults)) > maxWebsearch
 
-	for _, v := range entries {
-		if v.Provider == "websearch" && hideWebsearch && v.Text != wsprefix {
-			continue
-		}
+	entries = slices.DeleteFunc(entries, func(v *pb.QueryResponse_Item) bool {
+		return v.Provider == "websearch" && hideWebsearch && v.Text != wsprefix
+	})
+
+	page, next := storeResults(cid, currentQID, currentIteration, req, entries).page(int(req.Offset), int(req.Maxresults))
 
-		if req.Query != "" && currentIteration != queries[cid][currentQID].Iteration.Load() {
+	if stream {
+		// the client already replaced the streamed batches of a stale query
+		if stale() {
 			slog.Info("providers", "results", "aborting", "qid", currentQID, "iid", currentIteration)
 			return
 		}
 
-		req := pb.QueryResponse{
-			Qid:  int32(qid.Load()),
-			Iid:  int32(currentIteration),
-			Item: v,
+		writeCommit(currentQID, currentIteration, page, next, conn)
+
+		if len(page) == 0 {
+			writeStatus(QueryNoResults, conn)
 		}
 
-		b, err := proto.Marshal(&req)
-		if err != nil {
-			panic(err)
+		writeStatus(QueryDone, conn)
+	} else if !writeResults(currentQID, currentIteration, page, next, stale, conn) {
+		slog.Info("providers", "results", "aborting", "qid", currentQID, "iid", currentIteration)
+		return
+	}
+
+	slog.Info("providers", "results", len(page), "time", time.Since(start))
+}
+
+// writeResults sends the items followed by the done frame, the last item carries the token for the next page.
+// Returns false if sending got aborted because the query went stale.
+func writeResults(qid, iid uint32, items []*pb.QueryResponse_Item, next string, stale func() bool, conn net.Conn) bool {
+	if len(items) == 0 {
+		writeStatus(QueryNoResults, conn)
+		writeStatus(QueryDone, conn)
+
+		return true
+	}
+
+	addPreviews(items)
+
+	for k, v := range items {
+		if stale() {
+			return false
 		}
 
-		var buffer bytes.Buffer
-		buffer.Write([]byte{QueryItem})
+		resp := &pb.QueryResponse{
+			Qid:  int32(qid),
+			Iid:  int32(iid),
+			Item: v,
+		}
 
-		lengthBuf := make([]byte, 4)
-		binary.BigEndian.PutUint32(lengthBuf, uint32(len(b)))
-		buffer.Write(lengthBuf)
-		buffer.Write(b)
+		if k == len(items)-1 {
+			resp.NextPageToken = next
+		}
 
-		_, err = conn.Write(buffer.Bytes())
-		if err != nil {
+		if err := writeMessage(QueryItem, resp, conn); err != nil {
 			slog.Error("queryrequesthandler", "write", err)
-			return
+			return false
 		}
 	}
 
 	writeStatus(QueryDone, conn)
 
-	slog.Info("providers", "results", len(entries), "time", time.Since(start))
+	return true
+}
+
+// writeBatch sends the sorted results of a single provider as soon as they are ready. The rank is the best score in
+// the batch, frontends can use it to merge batches before the commit arrives.
+func writeBatch(qid, iid uint32, provider string, items []*pb.QueryResponse_Item, maxresults int, conn net.Conn) {
+	items = slices.Clone(items)
+	slices.SortFunc(items, sortEntries)
+
+	if len(items) > maxresults {
+		items = items[:maxresults]
+	}
+
+	addPreviews(items)
+
+	resp := &pb.QueryBatchResponse{
+		Qid:      int32(qid),
+		Iid:      int32(iid),
+		Provider: provider,
+		Items:    items,
+	}
+
+	if len(items) > 0 {
+		resp.Rank = items[0].Score
+	}
+
+	if err := writeMessage(QueryBatch, resp, conn); err != nil {
+		slog.Error("queryrequesthandler", "batch", err)
+	}
+}
+
+// writeCommit sends the final order of the already streamed items and the token for the next page.
+func writeCommit(qid, iid uint32, entries []*pb.QueryResponse_Item, next string, conn net.Conn) {
+	resp := &pb.QueryCommitResponse{
+		Qid:           int32(qid),
+		Iid:           int32(iid),
+		Items:         make([]*pb.QueryCommitResponse_Ref, 0, len(entries)),
+		NextPageToken: next,
+	}
+
+	for _, v := range entries {
+		resp.Items = append(resp.Items, &pb.QueryCommitResponse_Ref{
+			Provider:   v.Provider,
+			Identifier: v.Identifier,
+		})
+	}
+
+	if err := writeMessage(QueryCommit, resp, conn); err != nil {
+		slog.Error("queryrequesthandler", "commit", err)
+	}
+}
+
+// addPreviews fills in previews for providers creating them lazily.
+func addPreviews(entries []*pb.QueryResponse_Item) {
+	for _, v := range entries {
+		if v.Preview != "" {
+			continue
+		}
+
+		name := strings.Split(v.Provider, ":")[0]
+
+		if p, ok := providers.Providers[name].(provider.Previewer); ok {
+			v.Preview = p.Preview(v.Identifier)
+		}
+	}
 }
 
 func sortEntries(a *pb.QueryResponse_Item, b *pb.QueryResponse_Item) int {
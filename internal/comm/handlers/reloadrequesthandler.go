@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"log/slog"
+	"net"
+
+	"github.com/abenz1267/elephant/internal/providers"
+	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const ReloadDone = 0
+
+type ReloadRequest struct{}
+
+func (a *ReloadRequest) Handle(cid uint32, conn net.Conn, data []byte) {
+	req := &pb.ReloadRequest{}
+	if err := proto.Unmarshal(data, req); err != nil {
+		slog.Error("reloadrequesthandler", "protobuf", err)
+		writeError(pb.ErrorResponse_INVALID_REQUEST, err.Error(), conn)
+
+		return
+	}
+
+	if err := providers.Reload(); err != nil {
+		writeError(pb.ErrorResponse_COMMAND_FAILED, err.Error(), conn)
+
+		return
+	}
+
+	writeStatus(ReloadDone, conn)
+}
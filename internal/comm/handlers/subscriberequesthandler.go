@@ -3,7 +3,9 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
+	"fmt"
 	"log/slog"
 	"net"
 	"slices"
@@ -21,7 +23,20 @@ type SubscribeRequest struct{}
 func (a *SubscribeRequest) Handle(cid uint32, conn net.Conn, data []byte) {
 	req := &pb.SubscribeRequest{}
 	if err := proto.Unmarshal(data, req); err != nil {
-		slog.Error("activationrequesthandler", "protobuf", err)
+		slog.Error("subscriberequesthandler", "protobuf", err)
+		WriteError(cid, pb.ErrorResponse_INVALID_REQUEST, err.Error(), conn)
+
+		return
+	}
+
+	provider := req.Provider
+
+	if strings.HasPrefix(provider, "menus:") {
+		provider = strings.Split(provider, ":")[0]
+	}
+
+	if _, ok := providers.Providers[provider]; !ok {
+		WriteError(cid, pb.ErrorResponse_UNKNOWN_PROVIDER, fmt.Sprintf("unknown provider '%s'", req.Provider), conn)
 
 		return
 	}
@@ -51,6 +66,10 @@ func init() {
 	subs = make(map[uint32]*sub)
 	ProviderUpdated = make(chan string)
 
+	providers.OnUpdate = func(value string) {
+		ProviderUpdated <- value
+	}
+
 	// handle general realtime subs
 	go func() {
 		for p := range ProviderUpdated {
@@ -102,7 +121,7 @@ func watch(s *sub, conn net.Conn) {
 			return
 		}
 
-		res := p.Query(s.sid, s.sid, s.query, true, false)
+		res := p.Query(context.Background(), s.sid, s.sid, s.query, true, false)
 
 		slices.SortFunc(res, sortEntries)
 
@@ -2,8 +2,10 @@
 package common
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
+	"sync/atomic"
 
 	"github.com/knadh/koanf/parsers/toml/v2"
 	"github.com/knadh/koanf/providers/file"
@@ -18,54 +20,86 @@ type Config struct {
 
 type ElephantConfig struct {
 	ArgumentDelimiter string `koanf:"argument_delimited" desc:"global delimiter for arguments" default:"#"`
+	Socket            string `koanf:"socket" desc:"path of the socket, changes require a restart" default:"$XDG_RUNTIME_DIR/elephant/elephant.sock"`
 }
 
-var elephantConfig ElephantConfig
+// elephantConfig is swapped on reload while queries read it.
+var elephantConfig atomic.Pointer[ElephantConfig]
 
 func LoadGlobalConfig() {
-	elephantConfig = ElephantConfig{
-		ArgumentDelimiter: "#",
+	c := defaultElephantConfig()
+
+	LoadConfig("elephant", &c)
+
+	elephantConfig.Store(&c)
+}
+
+// ReloadGlobalConfig re-reads elephant.toml, the current config is kept on error.
+func ReloadGlobalConfig() error {
+	c := defaultElephantConfig()
+
+	if err := ReadConfig("elephant", &c); err != nil {
+		return err
 	}
 
-	LoadConfig("elephant", elephantConfig)
+	elephantConfig.Store(&c)
+
+	return nil
 }
 
+func defaultElephantConfig() ElephantConfig {
+	return ElephantConfig{
+		ArgumentDelimiter: "#",
+	}
+}
+
+// GetElephantConfig returns the current config, which must not be modified.
 func GetElephantConfig() *ElephantConfig {
-	return &elephantConfig
+	if c := elephantConfig.Load(); c != nil {
+		return c
+	}
+
+	c := defaultElephantConfig()
+
+	return &c
 }
 
+// LoadConfig loads elephant's own config and exits on error. Providers return the error of ReadConfig from Setup
+// instead.
 func LoadConfig(provider string, config any) {
+	if err := ReadConfig(provider, config); err != nil {
+		slog.Error(provider, "config", err)
+		os.Exit(1)
+	}
+}
+
+// ReadConfig merges the user config for the provider into config, which has to be a pointer holding the defaults.
+func ReadConfig(provider string, config any) error {
 	defaults := koanf.New(".")
 
 	err := defaults.Load(structs.Provider(config, "koanf"), nil)
 	if err != nil {
-		slog.Error(provider, "config", err)
-		os.Exit(1)
+		return err
 	}
 
 	userConfig := ProviderConfig(provider)
 
-	if FileExists(userConfig) {
-		user := koanf.New("")
-
-		err := user.Load(file.Provider(userConfig), toml.Parser())
-		if err != nil {
-			slog.Error(provider, "config", err)
-			os.Exit(1)
-		}
-
-		err = defaults.Merge(user)
-		if err != nil {
-			slog.Error(provider, "config", err)
-			os.Exit(1)
-		}
-
-		err = defaults.Unmarshal("", &config)
-		if err != nil {
-			slog.Error(provider, "config", err)
-			os.Exit(1)
-		}
-	} else {
+	if !FileExists(userConfig) {
 		slog.Info(provider, "config", "not found. using default config")
+		return nil
+	}
+
+	user := koanf.New("")
+
+	err = user.Load(file.Provider(userConfig), toml.Parser())
+	if err != nil {
+		return fmt.Errorf("%s: %w", userConfig, err)
 	}
+
+	err = defaults.Merge(user)
+	if err != nil {
+		return fmt.Errorf("%s: %w", userConfig, err)
+	}
+
+	return defaults.Unmarshal("", config)
 }
@@ -46,6 +46,24 @@ func ConfigDir() string {
 	return ""
 }
 
+// SocketPath returns the path of the socket the daemon listens on and clients connect to.
+// It's read from elephant.toml every time, so clients don't have to load the full config.
+func SocketPath() string {
+	c := defaultElephantConfig()
+
+	if FileExists(ProviderConfig("elephant")) {
+		if err := ReadConfig("elephant", &c); err != nil {
+			slog.Error("common", "socket", err)
+		}
+	}
+
+	if c.Socket != "" {
+		return os.ExpandEnv(c.Socket)
+	}
+
+	return filepath.Join(xdg.RuntimeDir, "elephant", "elephant.sock")
+}
+
 func CacheFile(file string) string {
 	d, _ := os.UserCacheDir()
 
@@ -2,76 +2,158 @@
 package history
 
 import (
-	"bytes"
-	"encoding/gob"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
-	"os"
-	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/abenz1267/elephant/internal/common"
+	"github.com/abenz1267/elephant/internal/common/store"
 )
 
 type HistoryData struct {
-	LastUsed time.Time
-	Amount   int
+	LastUsed time.Time `json:"last_used"`
+	Amount   int       `json:"amount"`
+	// usages per hour of the day and day of the week
+	Hours    [24]int `json:"hours,omitzero"`
+	Weekdays [7]int  `json:"weekdays,omitzero"`
 }
 
-// TODO: this is global for every history ... should not be the case. Just a crutch because of gob encoding.
-var mut sync.Mutex
+const (
+	// version is the schema version of the stored history. 2: keyed by stable keys, see Migrate.
+	version = 2
+	// saveDelay is how long changes are collected before the history is written.
+	saveDelay = 1 * time.Second
+	// maxRetryDelay caps the backoff of writes retried after an error.
+	maxRetryDelay = 5 * time.Minute
+	// maxAmount caps the recorded usages of an item, it's the upper bound for Scoring.MaxWeight.
+	maxAmount = 100
+)
+
+var (
+	// loaded holds all histories, so they can be flushed on shutdown.
+	loaded   []*History
+	loadedMu sync.Mutex
+)
 
 type History struct {
 	Provider string
 	Data     map[string]map[string]*HistoryData
+
+	mu sync.RWMutex
+	// writeMu keeps writes of the same history in order
+	writeMu sync.Mutex
+	// dirty is set while changes haven't been written successfully
+	dirty bool
+	timer *time.Timer
+	// retry is the delay of the next attempt after a failed write, zero after successful writes
+	retry time.Duration
+
+	scoring Scoring
+	model   Model
+
+	// stored is the schema version of Data
+	stored  int
+	migrate func(identifier string) (string, bool)
 }
 
+// Save records the usage of identifier for query. The history is written in the background, multiple saves within
+// saveDelay are written at once.
 func (h *History) Save(query, identifier string) {
-	mut.Lock()
-	defer mut.Unlock()
-
-	if _, ok := h.Data[query]; ok {
-		if val, ok := h.Data[query][identifier]; ok {
-			h.Data[query][identifier].LastUsed = time.Now()
-			h.Data[query][identifier].Amount = min(val.Amount+1, 10)
-		} else {
-			h.Data[query][identifier] = &HistoryData{
-				LastUsed: time.Now(),
-				Amount:   1,
-			}
-		}
-	} else {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+
+	if _, ok := h.Data[query]; !ok {
 		h.Data[query] = make(map[string]*HistoryData)
-		h.Data[query][identifier] = &HistoryData{
-			LastUsed: time.Now(),
-			Amount:   1,
-		}
 	}
 
-	var b bytes.Buffer
-	encoder := gob.NewEncoder(&b)
+	val, ok := h.Data[query][identifier]
+	if !ok {
+		val = &HistoryData{}
+		h.Data[query][identifier] = val
+	}
+
+	val.LastUsed = now
+	val.Amount = min(val.Amount+1, maxAmount)
+	val.Hours[now.Hour()]++
+	val.Weekdays[now.Weekday()]++
 
-	err := encoder.Encode(h)
-	if err != nil {
-		slog.Error("history", "encode", err)
+	h.changed()
+}
+
+// changed marks the history as dirty and schedules a write. The caller has to hold the lock.
+func (h *History) changed() {
+	h.dirty = true
+
+	if h.timer == nil {
+		h.timer = time.AfterFunc(saveDelay, h.write)
+	}
+}
+
+// write saves the history to disk if there are unsaved changes.
+func (h *History) write() {
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+
+	h.mu.Lock()
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+	}
+
+	dirty := h.dirty
+	h.dirty = false
+	h.mu.Unlock()
+
+	if !dirty {
 		return
 	}
 
-	err = os.MkdirAll(filepath.Dir(common.CacheFile(fmt.Sprintf("%s_history.gob", h.Provider))), 0755)
-	if err != nil {
-		slog.Error("history", "createdirs", err)
+	h.mu.RLock()
+	err := store.Save(file(h.Provider), h.stored, h)
+	h.mu.RUnlock()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err == nil {
+		h.retry = 0
 		return
 	}
 
-	err = os.WriteFile(common.CacheFile(fmt.Sprintf("%s_history.gob", h.Provider)), b.Bytes(), 0o600)
-	if err != nil {
-		slog.Error("history", "writefile", err)
+	h.retry = min(max(2*h.retry, saveDelay), maxRetryDelay)
+	h.dirty = true
+
+	slog.Error("history", "save", err, "retry", h.retry)
+
+	if h.timer == nil {
+		h.timer = time.AfterFunc(h.retry, h.write)
+	}
+}
+
+func file(provider string) string {
+	return common.CacheFile(fmt.Sprintf("%s_history.gob", provider))
+}
+
+// Flush writes all loaded histories with unsaved changes to disk, waiting for writes in progress.
+func Flush() {
+	loadedMu.Lock()
+	defer loadedMu.Unlock()
+
+	for _, h := range loaded {
+		h.write()
 	}
 }
 
 func (h *History) FindUsage(query, identifier string) (int, time.Time) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
 	var usage int
 	var lastUsed time.Time
 
@@ -101,53 +183,67 @@ func (h *History) FindUsage(query, identifier string) (int, time.Time) {
 	return usage, lastUsed
 }
 
-func (h *History) CalcUsageScore(query, identifier string) int32 {
-	amount, last := h.FindUsage(query, identifier)
-
-	if amount == 0 {
-		return 0
+// SetScoring sets the scoring used by CalcUsageScore, the current one is kept on error.
+func (h *History) SetScoring(s Scoring) error {
+	m, err := model(s.Model)
+	if err != nil {
+		return err
 	}
 
-	base := 10
+	h.mu.Lock()
+	h.scoring = s
+	h.model = m
+	h.mu.Unlock()
 
-	if amount > 0 {
-		today := time.Now()
-		duration := today.Sub(last)
-		days := int(duration.Hours() / 24)
+	return nil
+}
+
+// CalcUsageScore scores the usage of identifier for query with the configured scoring model. Usages recorded for
+// prefixes of the query count as well, for an empty query all usages count.
+func (h *History) CalcUsageScore(query, identifier string) int32 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
 
-		if days > 0 {
-			base -= days
+	matches := []Match{}
+
+	for k, v := range h.Data {
+		if query != "" && !strings.HasPrefix(query, k) {
+			continue
 		}
 
-		res := max(base*amount, 1)
+		if n, ok := v[identifier]; ok {
+			matches = append(matches, Match{HistoryData: n, Exact: query == "" || k == query})
+		}
+	}
 
-		return int32(res)
+	if len(matches) == 0 {
+		return 0
 	}
 
-	return 0
+	return h.model.Score(h.scoring, matches, time.Now())
 }
 
 func Load(provider string) *History {
-	h := History{
+	h := &History{
 		Data:     make(map[string]map[string]*HistoryData),
 		Provider: provider,
+		scoring:  DefaultScoring(),
+		model:    linear{},
+		stored:   version,
 	}
 
-	file := common.CacheFile(fmt.Sprintf("%s_history.gob", provider))
-
-	if common.FileExists(file) {
-		f, err := os.ReadFile(file)
-		if err != nil {
-			slog.Error("history", "load", err)
-		} else {
-			decoder := gob.NewDecoder(bytes.NewReader(f))
+	stored, err := store.Load(file(provider), h)
 
-			err = decoder.Decode(&h)
-			if err != nil {
-				slog.Error("history", "decoding", err)
-			}
-		}
+	switch {
+	case err == nil:
+		h.stored = stored
+	case !errors.Is(err, fs.ErrNotExist):
+		slog.Error("history", "load", err)
 	}
 
-	return &h
+	loadedMu.Lock()
+	loaded = append(loaded, h)
+	loadedMu.Unlock()
+
+	return h
 }
@@ -0,0 +1,50 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteRetriesAfterError(t *testing.T) {
+	cache := t.TempDir()
+
+	// a file as cache dir makes saving fail
+	blocked := filepath.Join(cache, "blocked")
+	if err := os.WriteFile(blocked, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("XDG_CACHE_HOME", blocked)
+
+	h := Load("test")
+	h.Save("", "a")
+
+	for _, want := range []time.Duration{saveDelay, 2 * saveDelay, 4 * saveDelay} {
+		h.write()
+
+		h.mu.RLock()
+		retry, dirty, armed := h.retry, h.dirty, h.timer != nil
+		h.mu.RUnlock()
+
+		if retry != want || !dirty || !armed {
+			t.Fatalf("got retry %s, dirty %t, timer %t, want retry %s", retry, dirty, armed, want)
+		}
+	}
+
+	t.Setenv("XDG_CACHE_HOME", cache)
+	h.write()
+
+	h.mu.RLock()
+	retry, dirty := h.retry, h.dirty
+	h.mu.RUnlock()
+
+	if retry != 0 || dirty {
+		t.Errorf("got retry %s, dirty %t after successful write", retry, dirty)
+	}
+
+	if _, err := os.Stat(file("test")); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,280 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Entry is the usage of an identifier for a query.
+type Entry struct {
+	Query      string
+	Identifier string
+	HistoryData
+}
+
+// Export is the JSON format used to move histories between machines.
+type Export struct {
+	Version   int                                           `json:"version"`
+	Histories map[string]map[string]map[string]*HistoryData `json:"histories"`
+}
+
+// Get returns the loaded history of the provider.
+func Get(provider string) (*History, bool) {
+	loadedMu.Lock()
+	defer loadedMu.Unlock()
+
+	for _, h := range loaded {
+		if h.Provider == provider {
+			return h, true
+		}
+	}
+
+	return nil, false
+}
+
+// Providers returns the names of all providers with a loaded history.
+func Providers() []string {
+	loadedMu.Lock()
+	defer loadedMu.Unlock()
+
+	res := []string{}
+
+	for _, h := range loaded {
+		res = append(res, h.Provider)
+	}
+
+	slices.Sort(res)
+
+	return slices.Compact(res)
+}
+
+// Entries returns all entries sorted by query and identifier.
+func (h *History) Entries() []Entry {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	res := []Entry{}
+
+	for q, v := range h.Data {
+		for i, d := range v {
+			res = append(res, Entry{Query: q, Identifier: i, HistoryData: *d})
+		}
+	}
+
+	slices.SortFunc(res, func(a, b Entry) int {
+		if c := strings.Compare(a.Query, b.Query); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Identifier, b.Identifier)
+	})
+
+	return res
+}
+
+// Forget removes the usages of identifier, for all queries if query is empty. Returns the amount of removed entries.
+func (h *History) Forget(identifier, query string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	removed := 0
+
+	for q, v := range h.Data {
+		if query != "" && q != query {
+			continue
+		}
+
+		if _, ok := v[identifier]; ok {
+			delete(v, identifier)
+			removed++
+		}
+
+		if len(v) == 0 {
+			delete(h.Data, q)
+		}
+	}
+
+	if removed > 0 {
+		h.changed()
+	}
+
+	return removed
+}
+
+// Prune removes entries last used before the given time. Returns the amount of removed entries.
+func (h *History) Prune(before time.Time) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	removed := 0
+
+	for q, v := range h.Data {
+		for i, d := range v {
+			if d.LastUsed.Before(before) {
+				delete(v, i)
+				removed++
+			}
+		}
+
+		if len(v) == 0 {
+			delete(h.Data, q)
+		}
+	}
+
+	if removed > 0 {
+		h.changed()
+	}
+
+	return removed
+}
+
+// ExportJSON exports the histories of the given providers, all loaded histories if none are given.
+func ExportJSON(providers []string) ([]byte, error) {
+	if len(providers) == 0 {
+		providers = Providers()
+	}
+
+	e := Export{
+		Version:   version,
+		Histories: make(map[string]map[string]map[string]*HistoryData),
+	}
+
+	for _, p := range providers {
+		h, ok := Get(p)
+		if !ok {
+			return nil, fmt.Errorf("no history for provider '%s'", p)
+		}
+
+		h.mu.RLock()
+
+		if h.stored < version {
+			h.mu.RUnlock()
+			return nil, fmt.Errorf("history of provider '%s' hasn't been migrated yet", p)
+		}
+
+		data := make(map[string]map[string]*HistoryData, len(h.Data))
+
+		for q, v := range h.Data {
+			data[q] = make(map[string]*HistoryData, len(v))
+
+			for i, d := range v {
+				c := *d
+				data[q][i] = &c
+			}
+		}
+
+		h.mu.RUnlock()
+
+		e.Histories[p] = data
+	}
+
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// ImportJSON merges exported histories into the loaded ones. Existing entries keep the higher usage and latest
+// timestamp, so importing the same export twice changes nothing. With replace the imported histories replace the
+// current ones instead. Exports without stable keys are migrated first. Returns the amount of imported entries and
+// the providers skipped as they have no history.
+func ImportJSON(b []byte, replace bool) (int, []string, error) {
+	e := Export{}
+
+	if err := json.Unmarshal(b, &e); err != nil {
+		return 0, nil, fmt.Errorf("invalid export: %w", err)
+	}
+
+	if e.Version > version {
+		return 0, nil, fmt.Errorf("export version %d is newer than supported version %d", e.Version, version)
+	}
+
+	skipped := []string{}
+	targets := make(map[*History]map[string]map[string]*HistoryData)
+
+	for p, data := range e.Histories {
+		h, ok := Get(p)
+		if !ok {
+			skipped = append(skipped, p)
+			continue
+		}
+
+		if e.Version < keysVersion {
+			h.mu.RLock()
+			fn := h.migrate
+			h.mu.RUnlock()
+
+			if fn == nil {
+				return 0, nil, fmt.Errorf("can't migrate export version %d for provider '%s'", e.Version, p)
+			}
+
+			data, _ = remap(data, fn)
+		}
+
+		targets[h] = data
+	}
+
+	imported := 0
+
+	for h, data := range targets {
+		imported += h.merge(data, replace)
+	}
+
+	slices.Sort(skipped)
+
+	return imported, skipped, nil
+}
+
+func (h *History) merge(data map[string]map[string]*HistoryData, replace bool) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if replace {
+		h.Data = make(map[string]map[string]*HistoryData)
+	}
+
+	imported := 0
+
+	for q, v := range data {
+		for i, d := range v {
+			if d == nil {
+				continue
+			}
+
+			if _, ok := h.Data[q]; !ok {
+				h.Data[q] = make(map[string]*HistoryData)
+			}
+
+			imported++
+
+			cur, ok := h.Data[q][i]
+			if !ok {
+				c := *d
+				c.Amount = min(c.Amount, maxAmount)
+				h.Data[q][i] = &c
+
+				continue
+			}
+
+			cur.Amount = min(max(cur.Amount, d.Amount), maxAmount)
+
+			if d.LastUsed.After(cur.LastUsed) {
+				cur.LastUsed = d.LastUsed
+			}
+
+			for k := range cur.Hours {
+				cur.Hours[k] = max(cur.Hours[k], d.Hours[k])
+			}
+
+			for k := range cur.Weekdays {
+				cur.Weekdays[k] = max(cur.Weekdays[k], d.Weekdays[k])
+			}
+		}
+	}
+
+	if imported > 0 || replace {
+		h.changed()
+	}
+
+	return imported
+}
@@ -0,0 +1,85 @@
+package history
+
+import (
+	"log/slog"
+)
+
+// keysVersion is the first version keyed by stable keys instead of provider specific identifiers like paths or
+// hashes.
+const keysVersion = 2
+
+// Migrate re-maps histories saved before keysVersion to stable keys. fn returns the stable key for an old identifier,
+// entries mapped to the same key are combined. Entries it can't map, f.e. of uninstalled binaries, are kept under
+// their old key and mapped by a later call once fn knows them again. fn is kept to migrate imports of old exports, so
+// providers have to call Migrate once their items are loaded, even if there is nothing to migrate.
+func (h *History) Migrate(fn func(identifier string) (string, bool)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.migrate = fn
+
+	data, mapped := remap(h.Data, fn)
+
+	if h.stored >= keysVersion && mapped == 0 {
+		return
+	}
+
+	h.Data = data
+	h.stored = version
+	h.changed()
+
+	slog.Info("history", "provider", h.Provider, "migrated", mapped)
+}
+
+// remap returns the data with all identifiers fn knows replaced by their stable key and the amount of re-mapped
+// entries.
+func remap(data map[string]map[string]*HistoryData, fn func(string) (string, bool)) (map[string]map[string]*HistoryData, int) {
+	res := make(map[string]map[string]*HistoryData)
+	mapped := 0
+
+	for q, v := range data {
+		for i, d := range v {
+			if d == nil {
+				continue
+			}
+
+			key, ok := fn(i)
+			if !ok {
+				key = i
+			} else if key != i {
+				mapped++
+			}
+
+			if _, ok := res[q]; !ok {
+				res[q] = make(map[string]*HistoryData)
+			}
+
+			if cur, ok := res[q][key]; ok {
+				cur.add(d)
+				continue
+			}
+
+			c := *d
+			res[q][key] = &c
+		}
+	}
+
+	return res, mapped
+}
+
+// add combines the usage of two entries for the same item.
+func (d *HistoryData) add(o *HistoryData) {
+	d.Amount = min(d.Amount+o.Amount, maxAmount)
+
+	if o.LastUsed.After(d.LastUsed) {
+		d.LastUsed = o.LastUsed
+	}
+
+	for k := range d.Hours {
+		d.Hours[k] += o.Hours[k]
+	}
+
+	for k := range d.Weekdays {
+		d.Weekdays[k] += o.Weekdays[k]
+	}
+}
@@ -0,0 +1,165 @@
+package history
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scoring configures how usage is turned into a score. Providers embed it in their config.
+type Scoring struct {
+	Model        string        `koanf:"model" desc:"scoring model, 'linear' or 'frecency'" default:"linear"`
+	HalfLife     time.Duration `koanf:"half_life" desc:"frecency: time after which usage counts half" default:"72h"`
+	MaxWeight    int           `koanf:"max_weight" desc:"max amount of usages counted per query" default:"10"`
+	PrefixWeight float64       `koanf:"prefix_weight" desc:"frecency: weight of usages recorded for a shorter prefix of the query, usages for the exact query weigh 1" default:"0.5"`
+	HourBoost    float64       `koanf:"hour_boost" desc:"frecency: boost for items usually used at the current hour, 0 to disable" default:"0"`
+	WeekdayBoost float64       `koanf:"weekday_boost" desc:"frecency: boost for items usually used on the current weekday, 0 to disable" default:"0"`
+}
+
+// DefaultScoring returns the scoring used if the provider doesn't configure one.
+func DefaultScoring() Scoring {
+	return Scoring{
+		Model:        "linear",
+		HalfLife:     72 * time.Hour,
+		MaxWeight:    10,
+		PrefixWeight: 0.5,
+	}
+}
+
+// Match is a history entry of an identifier, recorded for the query or a prefix of it.
+type Match struct {
+	*HistoryData
+	// Exact is set if the entry was recorded for exactly the query.
+	Exact bool
+}
+
+// Model calculates the usage score of an identifier from its matching history entries.
+type Model interface {
+	Score(s Scoring, matches []Match, now time.Time) int32
+}
+
+var (
+	models = map[string]Model{
+		"linear":   linear{},
+		"frecency": frecency{},
+	}
+	modelsMu sync.RWMutex
+)
+
+// RegisterModel adds a scoring model selectable by name via Scoring.Model.
+func RegisterModel(name string, m Model) {
+	modelsMu.Lock()
+	defer modelsMu.Unlock()
+
+	models[name] = m
+}
+
+// Models returns the names of all scoring models.
+func Models() []string {
+	modelsMu.RLock()
+	defer modelsMu.RUnlock()
+
+	res := make([]string, 0, len(models))
+
+	for k := range models {
+		res = append(res, k)
+	}
+
+	slices.Sort(res)
+
+	return res
+}
+
+func model(name string) (Model, error) {
+	modelsMu.RLock()
+	m, ok := models[name]
+	modelsMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown scoring model '%s', available: %s", name, strings.Join(Models(), ", "))
+	}
+
+	return m, nil
+}
+
+// linear scores 10 points per usage, minus one per day since the last usage.
+type linear struct{}
+
+func (linear) Score(s Scoring, matches []Match, now time.Time) int32 {
+	var amount int
+	var last time.Time
+
+	for _, m := range matches {
+		amount += min(m.Amount, s.MaxWeight)
+
+		if m.LastUsed.After(last) {
+			last = m.LastUsed
+		}
+	}
+
+	if amount == 0 {
+		return 0
+	}
+
+	base := 10
+
+	if days := int(now.Sub(last).Hours() / 24); days > 0 {
+		base -= days
+	}
+
+	return int32(max(base*amount, 1))
+}
+
+// frecency decays usage exponentially by its age, prefers usages of the exact query and optionally boosts items
+// commonly used at the current time.
+type frecency struct{}
+
+// frecencyScale puts frecency scores in the same range as linear ones.
+const frecencyScale = 10
+
+func (frecency) Score(s Scoring, matches []Match, now time.Time) int32 {
+	var score float64
+
+	for _, m := range matches {
+		weight := 1.0
+		if !m.Exact {
+			weight = s.PrefixWeight
+		}
+
+		decay := 1.0
+		if s.HalfLife > 0 {
+			decay = math.Pow(0.5, float64(now.Sub(m.LastUsed))/float64(s.HalfLife))
+		}
+
+		boost := 1.0
+
+		if total := sum(m.Hours[:]); total > 0 {
+			boost += s.HourBoost * float64(m.Hours[now.Hour()]) / float64(total)
+		}
+
+		if total := sum(m.Weekdays[:]); total > 0 {
+			boost += s.WeekdayBoost * float64(m.Weekdays[now.Weekday()]) / float64(total)
+		}
+
+		score += weight * float64(min(m.Amount, s.MaxWeight)) * decay * boost
+	}
+
+	if score <= 0 {
+		return 0
+	}
+
+	return int32(max(math.Round(score*frecencyScale), 1))
+}
+
+func sum(v []int) int {
+	res := 0
+
+	for _, n := range v {
+		res += n
+	}
+
+	return res
+}
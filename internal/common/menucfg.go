@@ -8,6 +8,10 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/charlievieth/fastwalk"
 	"github.com/pelletier/go-toml/v2"
@@ -27,6 +31,9 @@ type Menu struct {
 	GlobalSearch         bool    `toml:"global_search"`
 	HideFromProviderlist bool    `toml:"hide_from_providerlist"`
 	Entries              []Entry `toml:"entries"`
+
+	// File is the definition the menu was loaded from.
+	File string `toml:"-"`
 }
 
 type Entry struct {
@@ -49,30 +56,62 @@ func (e Entry) CreateIdentifier() string {
 }
 
 var (
-	MenuConfigLoaded MenuConfig
-	menuname         = "menus"
-	Menus            = make(map[string]Menu)
+	menuConfig atomic.Pointer[MenuConfig]
+	menuname   = "menus"
+	// menus is replaced on every change, never modified in place, so it can be read without locking.
+	menus   atomic.Pointer[map[string]Menu]
+	menusMu sync.Mutex
 )
 
+// GetMenuConfig returns the current menus config, which must not be modified.
+func GetMenuConfig() *MenuConfig {
+	if c := menuConfig.Load(); c != nil {
+		return c
+	}
+
+	return &MenuConfig{}
+}
+
+// Menus returns all loaded menus, the map must not be modified.
+func Menus() map[string]Menu {
+	if m := menus.Load(); m != nil {
+		return *m
+	}
+
+	return map[string]Menu{}
+}
+
 func LoadMenus() {
-	MenuConfigLoaded = MenuConfig{
+	if err := ReloadMenus(); err != nil {
+		slog.Error(menuname, "load", err)
+		os.Exit(1)
+	}
+}
+
+// ReloadMenus re-reads the menus config and all menu definitions. The current menus are kept on error.
+func ReloadMenus() error {
+	cfg := MenuConfig{
 		Config: Config{
 			MinScore: 10,
 		},
 		Paths: []string{},
 	}
 
-	LoadConfig(menuname, MenuConfigLoaded)
+	if err := ReadConfig(menuname, &cfg); err != nil {
+		return err
+	}
 
-	path := filepath.Join(ConfigDir(), "menus")
+	cfg.Paths = append(cfg.Paths, filepath.Join(ConfigDir(), "menus"))
 
-	MenuConfigLoaded.Paths = append(MenuConfigLoaded.Paths, path)
+	parsed := []Menu{}
 
 	conf := fastwalk.Config{
 		Follow: true,
 	}
 
-	for _, root := range MenuConfigLoaded.Paths {
+	var mut sync.Mutex
+
+	for _, root := range cfg.Paths {
 		if _, err := os.Stat(root); err != nil {
 			continue
 		}
@@ -82,37 +121,153 @@ func LoadMenus() {
 				return err
 			}
 
-			if d.IsDir() {
+			if d.IsDir() || !IsMenuFile(path) {
 				return nil
 			}
 
-			m := Menu{}
-
-			b, err := os.ReadFile(path)
+			m, err := ParseMenu(path)
 			if err != nil {
 				slog.Error(menuname, "setup", err)
+				return nil
 			}
 
-			err = toml.Unmarshal(b, &m)
-			if err != nil {
-				slog.Error(menuname, "setup", err)
-			}
+			mut.Lock()
+			parsed = append(parsed, m)
+			mut.Unlock()
 
-			for k, v := range m.Entries {
-				m.Entries[k].Menu = m.Name
-				m.Entries[k].Identifier = v.CreateIdentifier()
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
 
-				if v.SubMenu != "" {
-					m.Entries[k].Identifier = fmt.Sprintf("keepopen:menus:%s", v.SubMenu)
-				}
-			}
+	// like validation, the first file defining a menu wins
+	slices.SortFunc(parsed, func(a, b Menu) int { return strings.Compare(a.File, b.File) })
 
-			Menus[m.Name] = m
+	loaded := make(map[string]Menu)
 
-			return nil
-		}); err != nil {
-			slog.Error(menuname, "walk", err)
-			os.Exit(1)
+	for _, m := range parsed {
+		if prev, ok := loaded[m.Name]; ok {
+			slog.Error(menuname, "duplicate", m.Name, "file", m.File, "defined in", prev.File)
+			continue
+		}
+
+		loaded[m.Name] = m
+	}
+
+	menusMu.Lock()
+	defer menusMu.Unlock()
+
+	menuConfig.Store(&cfg)
+	menus.Store(&loaded)
+
+	return nil
+}
+
+// IsMenuFile checks if the file is a menu definition.
+func IsMenuFile(path string) bool {
+	return filepath.Ext(path) == ".toml" && !strings.HasPrefix(filepath.Base(path), ".")
+}
+
+// ParseMenu reads and validates a single menu definition.
+func ParseMenu(path string) (Menu, error) {
+	m := Menu{}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+
+	if err := toml.Unmarshal(b, &m); err != nil {
+		return m, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if m.Name == "" {
+		return m, fmt.Errorf("%s: menu has no name", path)
+	}
+
+	entries := make([]Entry, 0, len(m.Entries))
+
+	for k, v := range m.Entries {
+		if v.Text == "" && v.Async == "" {
+			slog.Error(menuname, "menu", m.Name, "file", path, "skipping", fmt.Sprintf("entry %d has neither text nor async", k+1))
+			continue
+		}
+
+		v.Menu = m.Name
+		v.Identifier = v.CreateIdentifier()
+
+		if v.SubMenu != "" {
+			v.Identifier = fmt.Sprintf("keepopen:menus:%s", v.SubMenu)
 		}
+
+		entries = append(entries, v)
+	}
+
+	m.Entries = entries
+
+	m.File = path
+
+	return m, nil
+}
+
+// UpdateMenuFile re-parses the menu definition at path. Menus previously loaded from the file are replaced,
+// if the file is invalid the current menus are kept. Menus already defined in another file are kept as well.
+// Returns the names of all changed menus.
+func UpdateMenuFile(path string) ([]string, error) {
+	m, err := ParseMenu(path)
+	if err != nil {
+		return nil, err
 	}
+
+	menusMu.Lock()
+	defer menusMu.Unlock()
+
+	updated, changed := withoutFile(path)
+
+	if prev, ok := updated[m.Name]; ok {
+		slog.Error(menuname, "duplicate", m.Name, "file", path, "defined in", prev.File)
+	} else {
+		updated[m.Name] = m
+
+		if !slices.Contains(changed, m.Name) {
+			changed = append(changed, m.Name)
+		}
+	}
+
+	menus.Store(&updated)
+
+	return changed, nil
+}
+
+// RemoveMenuFile drops all menus loaded from path and returns their names.
+func RemoveMenuFile(path string) []string {
+	menusMu.Lock()
+	defer menusMu.Unlock()
+
+	updated, changed := withoutFile(path)
+
+	if len(changed) > 0 {
+		menus.Store(&updated)
+	}
+
+	return changed
+}
+
+// withoutFile copies the current menus, leaving out the ones loaded from path or any file below it.
+func withoutFile(path string) (map[string]Menu, []string) {
+	current := Menus()
+	res := make(map[string]Menu, len(current))
+	removed := []string{}
+
+	for k, v := range current {
+		if v.File == path || strings.HasPrefix(v.File, path+string(filepath.Separator)) {
+			removed = append(removed, k)
+			continue
+		}
+
+		res[k] = v
+	}
+
+	return res, removed
 }
@@ -0,0 +1,89 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeMenu writes a menu definition with a single entry to the menus dir.
+func writeMenu(t *testing.T, dir, file, name, text string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "menus", file)
+	content := "name = \"" + name + "\"\n\n[[entries]]\ntext = \"" + text + "\"\naction = \"true\"\n"
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func setupMenus(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	if err := os.Mkdir(filepath.Join(dir, "menus"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	SetExplicitDir(dir)
+	t.Cleanup(func() { SetExplicitDir("") })
+
+	return dir
+}
+
+func TestReloadMenusKeepsFirstDuplicate(t *testing.T) {
+	dir := setupMenus(t)
+
+	writeMenu(t, dir, "a.toml", "power", "first")
+	writeMenu(t, dir, "b.toml", "power", "second")
+
+	if err := ReloadMenus(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := Menus()["power"].Entries[0].Text; got != "first" {
+		t.Errorf("got %q, want %q", got, "first")
+	}
+}
+
+func TestUpdateMenuFileKeepsOtherFilesMenu(t *testing.T) {
+	dir := setupMenus(t)
+
+	writeMenu(t, dir, "a.toml", "power", "first")
+
+	if err := ReloadMenus(); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := UpdateMenuFile(writeMenu(t, dir, "b.toml", "power", "second"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changed) != 0 {
+		t.Errorf("got changed %v, want none", changed)
+	}
+
+	if got := Menus()["power"].Entries[0].Text; got != "first" {
+		t.Errorf("got %q, want %q", got, "first")
+	}
+
+	// the menu can still be changed by its own file
+	changed, err = UpdateMenuFile(writeMenu(t, dir, "a.toml", "power", "changed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(changed, []string{"power"}) {
+		t.Errorf("got changed %v, want [power]", changed)
+	}
+
+	if got := Menus()["power"].Entries[0].Text; got != "changed" {
+		t.Errorf("got %q, want %q", got, "changed")
+	}
+}
@@ -0,0 +1,217 @@
+// Package store provides crash-safe persistence for histories and caches.
+//
+// Data is gob encoded and prefixed with a header containing the schema version of the data and a checksum.
+// Files are written to a temporary file, synced and renamed over the old one, which is kept as backup.
+// Corrupt files are detected on load and the backup is used instead.
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// ErrCorrupt is returned if neither the file nor its backup could be read.
+var ErrCorrupt = errors.New("store corrupt")
+
+const (
+	magic = "ELPH"
+	// format is the version of the file layout itself, not of the stored data.
+	format = 1
+	// magic, format, version, length, checksum
+	headerSize = 4 + 1 + 4 + 8 + 4
+)
+
+// Backup returns the path of the backup of file.
+func Backup(file string) string {
+	return file + ".bak"
+}
+
+// Save encodes v and writes it to file, tagged with the schema version of v. The previous file is kept as backup.
+func Save(file string, version int, v any) error {
+	var payload bytes.Buffer
+
+	if err := gob.NewEncoder(&payload).Encode(v); err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+
+	b := make([]byte, headerSize, headerSize+payload.Len())
+	copy(b, magic)
+	b[4] = format
+	binary.BigEndian.PutUint32(b[5:9], uint32(version))
+	binary.BigEndian.PutUint64(b[9:17], uint64(payload.Len()))
+	binary.BigEndian.PutUint32(b[17:21], crc32.ChecksumIEEE(payload.Bytes()))
+	b = append(b, payload.Bytes()...)
+
+	return write(file, b)
+}
+
+// write writes b to a synced temporary file, moves the current file to the backup and renames the temporary file.
+// If a crash happens in between, Load falls back to the backup. Corrupt files are replaced without becoming the backup.
+func write(file string, b []byte) error {
+	dir := filepath.Dir(file)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, fmt.Sprintf(".%s.*.tmp", filepath.Base(file)))
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	// a corrupt file would replace the backup, which Load might have used instead
+	if valid(file) {
+		if err := os.Rename(file, Backup(file)); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+// syncDir makes the renames durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
+
+// Load decodes file into v, which has to be a pointer, and returns the schema version the data was saved with, so
+// callers can migrate older data. If the file is corrupt, the backup is loaded. Plain gob files written before the
+// store existed are loaded as version 0. Returns an error wrapping fs.ErrNotExist if there is no data yet.
+func Load(file string, v any) (int, error) {
+	version, err := load(file, v)
+	if err == nil {
+		return version, nil
+	}
+
+	if errors.Is(err, fs.ErrNotExist) && !exists(Backup(file)) {
+		return 0, err
+	}
+
+	slog.Warn("store", "file", file, "error", err, "using", "backup")
+
+	version, bakErr := load(Backup(file), v)
+	if bakErr != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, fmt.Errorf("%w: %s: %w", ErrCorrupt, Backup(file), bakErr)
+		}
+
+		return 0, fmt.Errorf("%w: %s: %w, backup: %w", ErrCorrupt, file, err, bakErr)
+	}
+
+	return version, nil
+}
+
+func load(file string, v any) (int, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+
+	if !bytes.HasPrefix(b, []byte(magic)) {
+		// legacy plain gob file
+		if err := gob.NewDecoder(bytes.NewReader(b)).Decode(v); err != nil {
+			return 0, fmt.Errorf("decode: %w", err)
+		}
+
+		return 0, nil
+	}
+
+	version, payload, err := verify(b)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(v); err != nil {
+		return 0, fmt.Errorf("decode: %w", err)
+	}
+
+	return version, nil
+}
+
+// verify checks the header and checksum of b and returns the schema version and the payload.
+func verify(b []byte) (int, []byte, error) {
+	if len(b) < headerSize {
+		return 0, nil, errors.New("truncated header")
+	}
+
+	if b[4] != format {
+		return 0, nil, fmt.Errorf("unsupported format %d", b[4])
+	}
+
+	version := int(binary.BigEndian.Uint32(b[5:9]))
+	length := binary.BigEndian.Uint64(b[9:17])
+	checksum := binary.BigEndian.Uint32(b[17:21])
+	payload := b[headerSize:]
+
+	if uint64(len(payload)) != length {
+		return 0, nil, fmt.Errorf("expected %d bytes, got %d", length, len(payload))
+	}
+
+	if crc32.ChecksumIEEE(payload) != checksum {
+		return 0, nil, errors.New("checksum mismatch")
+	}
+
+	return version, payload, nil
+}
+
+// valid reports if file exists and is intact.
+func valid(file string) bool {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return false
+	}
+
+	if !bytes.HasPrefix(b, []byte(magic)) {
+		// legacy files are checked by decoding and discarding the value
+		return gob.NewDecoder(bytes.NewReader(b)).DecodeValue(reflect.Value{}) == nil
+	}
+
+	_, _, err = verify(b)
+
+	return err == nil
+}
+
+func exists(file string) bool {
+	_, err := os.Stat(file)
+	return err == nil
+}
@@ -0,0 +1,160 @@
+package store
+
+import (
+	"encoding/gob"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type data struct {
+	Items map[string]int
+}
+
+func testFile(t *testing.T) string {
+	t.Helper()
+
+	return filepath.Join(t.TempDir(), "test.gob")
+}
+
+func save(t *testing.T, file string, items map[string]int) {
+	t.Helper()
+
+	if err := Save(file, 1, data{Items: items}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func loadItems(t *testing.T, file string) (map[string]int, error) {
+	t.Helper()
+
+	var d data
+
+	version, err := Load(file, &d)
+	if err == nil && version != 1 {
+		t.Errorf("got version %d, want 1", version)
+	}
+
+	return d.Items, err
+}
+
+// corrupt modifies the stored file.
+func corrupt(t *testing.T, file string, fn func(b []byte) []byte) {
+	t.Helper()
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(file, fn(b), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := testFile(t)
+
+	if _, err := loadItems(t, file); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, want not exist", err)
+	}
+
+	save(t, file, map[string]int{"a": 1})
+
+	items, err := loadItems(t, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if items["a"] != 1 {
+		t.Errorf("got %v", items)
+	}
+}
+
+func TestLoadLegacy(t *testing.T) {
+	file := testFile(t)
+
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gob.NewEncoder(f).Encode(data{Items: map[string]int{"a": 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	f.Close()
+
+	var d data
+
+	version, err := Load(file, &d)
+	if err != nil || version != 0 || d.Items["a"] != 1 {
+		t.Errorf("got version %d, items %v, error %v", version, d.Items, err)
+	}
+}
+
+func TestBackupFallback(t *testing.T) {
+	tests := map[string]func(b []byte) []byte{
+		"truncated":        func(b []byte) []byte { return b[:len(b)-3] },
+		"truncated header": func(b []byte) []byte { return b[:10] },
+		"empty":            func(b []byte) []byte { return nil },
+		"checksum": func(b []byte) []byte {
+			b[len(b)-1] ^= 0xff
+			return b
+		},
+	}
+
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			file := testFile(t)
+
+			save(t, file, map[string]int{"a": 1})
+			save(t, file, map[string]int{"a": 2})
+			corrupt(t, file, fn)
+
+			items, err := loadItems(t, file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if items["a"] != 1 {
+				t.Errorf("got %v, want the backup", items)
+			}
+		})
+	}
+}
+
+func TestSaveKeepsBackupOfCorruptFile(t *testing.T) {
+	file := testFile(t)
+
+	save(t, file, map[string]int{"a": 1})
+	save(t, file, map[string]int{"a": 2})
+	corrupt(t, file, func(b []byte) []byte { return b[:len(b)-3] })
+
+	// the next save must not rotate the corrupt file over the backup
+	save(t, file, map[string]int{"a": 3})
+	corrupt(t, file, func(b []byte) []byte { return nil })
+
+	items, err := loadItems(t, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if items["a"] != 1 {
+		t.Errorf("got %v, want the backup", items)
+	}
+}
+
+func TestCorrupt(t *testing.T) {
+	file := testFile(t)
+
+	save(t, file, map[string]int{"a": 1})
+	save(t, file, map[string]int{"a": 2})
+	corrupt(t, file, func(b []byte) []byte { return b[:5] })
+	corrupt(t, Backup(file), func(b []byte) []byte { return b[:5] })
+
+	if _, err := loadItems(t, file); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("got %v, want ErrCorrupt", err)
+	}
+}
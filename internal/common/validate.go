@@ -0,0 +1,340 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Diagnostic is a single problem found while validating configs and menus.
+type Diagnostic struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s", d.File, d.Message)
+	}
+
+	return fmt.Sprintf("%s:%d: %s", d.File, d.Line, d.Message)
+}
+
+var (
+	reMenuName    = regexp.MustCompile(`(?m)^\s*name\s*=`)
+	reMenuEntries = regexp.MustCompile(`(?m)^\s*\[\[\s*entries\s*\]\]`)
+	reMenuSubMenu = regexp.MustCompile(`(?m)^\s*submenu\s*=`)
+)
+
+// ValidateConfig checks the users config file of the provider against config, a pointer to the providers config struct.
+func ValidateConfig(provider string, config any) []Diagnostic {
+	file := ProviderConfig(provider)
+	if !FileExists(file) {
+		return nil
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return []Diagnostic{{File: file, Message: err.Error()}}
+	}
+
+	doc := map[string]any{}
+
+	if err := toml.Unmarshal(b, &doc); err != nil {
+		return []Diagnostic{decodeDiagnostic(file, err)}
+	}
+
+	diags := checkKeys(file, b, doc, reflect.TypeOf(config), "")
+
+	if err := ReadConfig(provider, config); err != nil {
+		diags = append(diags, Diagnostic{File: file, Message: err.Error()})
+	}
+
+	return diags
+}
+
+// checkKeys reports all keys in doc that have no matching koanf tag in t.
+func checkKeys(file string, b []byte, doc map[string]any, t reflect.Type, prefix string) []Diagnostic {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := make(map[string]reflect.Type)
+	collectFields(t, fields)
+
+	diags := []Diagnostic{}
+
+	for k, v := range doc {
+		key := k
+		if prefix != "" {
+			key = fmt.Sprintf("%s.%s", prefix, k)
+		}
+
+		ft, ok := fields[k]
+		if !ok {
+			diags = append(diags, Diagnostic{File: file, Line: keyLine(b, k), Message: fmt.Sprintf("unknown key '%s'", key)})
+			continue
+		}
+
+		switch val := v.(type) {
+		case map[string]any:
+			diags = append(diags, checkKeys(file, b, val, ft, key)...)
+		case []any:
+			if ft.Kind() != reflect.Slice {
+				continue
+			}
+
+			for i, e := range val {
+				if m, ok := e.(map[string]any); ok {
+					diags = append(diags, checkKeys(file, b, m, ft.Elem(), fmt.Sprintf("%s[%d]", key, i))...)
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(diags, func(a, b Diagnostic) int {
+		return a.Line - b.Line
+	})
+
+	return diags
+}
+
+// collectFields maps koanf keys to their field types, squashed structs are flattened.
+func collectFields(t reflect.Type, fields map[string]reflect.Type) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("koanf")
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if opts == "squash" && f.Type.Kind() == reflect.Struct {
+			collectFields(f.Type, fields)
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+
+		fields[name] = f.Type
+	}
+}
+
+// keyLine returns the first line the key is set or used as a table on, 0 if it can't be found.
+func keyLine(b []byte, key string) int {
+	re := regexp.MustCompile(fmt.Sprintf(`(?m)^\s*(?:"?%[1]s"?\s*=|\[+[^\]]*\b%[1]s\s*\]+)`, regexp.QuoteMeta(key)))
+
+	loc := re.FindIndex(b)
+	if loc == nil {
+		return 0
+	}
+
+	return lineAt(b, loc[0])
+}
+
+func lineAt(b []byte, offset int) int {
+	return bytes.Count(b[:offset], []byte("\n")) + 1
+}
+
+func decodeDiagnostic(file string, err error) Diagnostic {
+	var derr *toml.DecodeError
+
+	if errors.As(err, &derr) {
+		row, _ := derr.Position()
+		return Diagnostic{File: file, Line: row, Message: derr.Error()}
+	}
+
+	return Diagnostic{File: file, Message: err.Error()}
+}
+
+type menuFile struct {
+	menu Menu
+	file string
+	b    []byte
+}
+
+// entryLine returns the offset and line of the entry at index i.
+func (m menuFile) entryLine(i int) (int, int) {
+	locs := reMenuEntries.FindAllIndex(m.b, -1)
+	if i >= len(locs) {
+		return 0, 0
+	}
+
+	return locs[i][0], lineAt(m.b, locs[i][0])
+}
+
+func (m menuFile) nameLine() int {
+	if loc := reMenuName.FindIndex(m.b); loc != nil {
+		return lineAt(m.b, loc[0])
+	}
+
+	return 0
+}
+
+func (m menuFile) subMenuLine(i int) int {
+	offset, line := m.entryLine(i)
+
+	if loc := reMenuSubMenu.FindIndex(m.b[offset:]); loc != nil {
+		return lineAt(m.b, offset+loc[0])
+	}
+
+	return line
+}
+
+// ValidateMenus checks menus.toml and all menu definitions for unknown keys, missing names, duplicates,
+// entries without action, dangling submenus and submenu cycles.
+func ValidateMenus() []Diagnostic {
+	cfg := MenuConfig{
+		Paths: []string{},
+	}
+
+	diags := ValidateConfig(menuname, &cfg)
+
+	paths := append(cfg.Paths, filepath.Join(ConfigDir(), "menus"))
+	files := []string{}
+
+	for _, root := range paths {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && IsMenuFile(path) {
+				files = append(files, path)
+			}
+
+			return nil
+		})
+	}
+
+	slices.Sort(files)
+
+	menus := make(map[string]menuFile)
+	order := []string{}
+
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			diags = append(diags, Diagnostic{File: file, Message: err.Error()})
+			continue
+		}
+
+		mf := menuFile{file: file, b: b}
+
+		decoder := toml.NewDecoder(bytes.NewReader(b))
+		decoder.DisallowUnknownFields()
+
+		var strict *toml.StrictMissingError
+
+		if err := decoder.Decode(&mf.menu); err != nil {
+			if !errors.As(err, &strict) {
+				diags = append(diags, decodeDiagnostic(file, err))
+				continue
+			}
+
+			for _, e := range strict.Errors {
+				row, _ := e.Position()
+				diags = append(diags, Diagnostic{File: file, Line: row, Message: fmt.Sprintf("unknown key '%s'", strings.Join(e.Key(), "."))})
+			}
+		}
+
+		if mf.menu.Name == "" {
+			diags = append(diags, Diagnostic{File: file, Line: 1, Message: "menu has no name"})
+			continue
+		}
+
+		if prev, ok := menus[mf.menu.Name]; ok {
+			diags = append(diags, Diagnostic{File: file, Line: mf.nameLine(), Message: fmt.Sprintf("duplicate menu '%s', already defined in %s", mf.menu.Name, prev.file)})
+			continue
+		}
+
+		for i, e := range mf.menu.Entries {
+			_, line := mf.entryLine(i)
+
+			if e.Text == "" && e.Async == "" {
+				diags = append(diags, Diagnostic{File: file, Line: line, Message: "entry has neither text nor async"})
+			}
+
+			// async entries without action are used to display information
+			if e.SubMenu == "" && e.Action == "" && e.Value == "" && e.Async == "" && mf.menu.Action == "" {
+				diags = append(diags, Diagnostic{File: file, Line: line, Message: "entry has neither action, value nor submenu and the menu has no default action"})
+			}
+		}
+
+		menus[mf.menu.Name] = mf
+		order = append(order, mf.menu.Name)
+	}
+
+	for _, name := range order {
+		mf := menus[name]
+
+		for i, e := range mf.menu.Entries {
+			if e.SubMenu == "" {
+				continue
+			}
+
+			if _, ok := menus[e.SubMenu]; !ok {
+				diags = append(diags, Diagnostic{File: mf.file, Line: mf.subMenuLine(i), Message: fmt.Sprintf("submenu '%s' does not exist", e.SubMenu)})
+			}
+		}
+	}
+
+	return append(diags, menuCycles(menus, order)...)
+}
+
+// menuCycles reports every submenu reference closing a cycle.
+func menuCycles(menus map[string]menuFile, order []string) []Diagnostic {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int)
+	diags := []Diagnostic{}
+
+	var visit func(name string, path []string)
+
+	visit = func(name string, path []string) {
+		state[name] = visiting
+		path = append(path, name)
+
+		mf := menus[name]
+
+		for i, e := range mf.menu.Entries {
+			if _, ok := menus[e.SubMenu]; !ok {
+				continue
+			}
+
+			switch state[e.SubMenu] {
+			case visiting:
+				start := slices.Index(path, e.SubMenu)
+				cycle := append(slices.Clone(path[start:]), e.SubMenu)
+
+				diags = append(diags, Diagnostic{File: mf.file, Line: mf.subMenuLine(i), Message: fmt.Sprintf("submenu cycle: %s", strings.Join(cycle, " -> "))})
+			case unvisited:
+				visit(e.SubMenu, path)
+			}
+		}
+
+		state[name] = visited
+	}
+
+	for _, name := range order {
+		if state[name] == unvisited {
+			visit(name, nil)
+		}
+	}
+
+	return diags
+}
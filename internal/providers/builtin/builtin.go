@@ -0,0 +1,28 @@
+//go:build builtin
+
+package builtin
+
+import (
+	"github.com/abenz1267/elephant/internal/providers"
+	"github.com/abenz1267/elephant/internal/providers/calc"
+	"github.com/abenz1267/elephant/internal/providers/clipboard"
+	"github.com/abenz1267/elephant/internal/providers/desktopapplications"
+	"github.com/abenz1267/elephant/internal/providers/files"
+	"github.com/abenz1267/elephant/internal/providers/menus"
+	"github.com/abenz1267/elephant/internal/providers/providerlist"
+	"github.com/abenz1267/elephant/internal/providers/runner"
+	"github.com/abenz1267/elephant/internal/providers/symbols"
+	"github.com/abenz1267/elephant/internal/providers/websearch"
+)
+
+func init() {
+	providers.Register(calc.Provider)
+	providers.Register(clipboard.Provider)
+	providers.Register(desktopapplications.Provider)
+	providers.Register(files.Provider)
+	providers.Register(menus.Provider)
+	providers.Register(providerlist.Provider)
+	providers.Register(runner.Provider)
+	providers.Register(symbols.Provider)
+	providers.Register(websearch.Provider)
+}
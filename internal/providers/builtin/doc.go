@@ -0,0 +1,8 @@
+// Package builtin compiles the bundled providers into the elephant binary.
+//
+// Providers are only included when building with the "builtin" tag:
+//
+//	go build -tags builtin ./cmd/elephant.go
+//
+// Without the tag this package is empty and all providers have to be installed as plugins.
+package builtin
@@ -1,37 +1,73 @@
-package main
+// Package calc provides calculations and unit conversion using qalc.
+package calc
 
 import (
-	"bytes"
+	"context"
 	"crypto/md5"
-	"encoding/gob"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
-	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unicode"
 
 	"github.com/abenz1267/elephant/internal/common"
+	"github.com/abenz1267/elephant/internal/common/store"
 	"github.com/abenz1267/elephant/internal/providers"
 	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"github.com/abenz1267/elephant/pkg/provider"
 )
 
 var (
 	Name       = "calc"
 	NamePretty = "Calculator/Unit-Conversion"
-	config     *Config
+	config     atomic.Pointer[Config]
 )
 
+type plugin struct{}
+
+// Provider is looked up by elephant when loading the plugin.
+var Provider provider.Provider = plugin{}
+
+func (plugin) APIVersion() int {
+	return provider.APIVersion
+}
+
+func (plugin) Name() string {
+	return Name
+}
+
+func (plugin) NamePretty() string {
+	return NamePretty
+}
+
 const (
 	ActionCopy   = "copy"
 	ActionSave   = "save"
 	ActionDelete = "delete"
 )
 
+// Actions returns the actions supported by Activate.
+func (plugin) Actions() []string {
+	return []string{ActionCopy, ActionSave, ActionDelete}
+}
+
+var (
+	resultActions = []*pb.QueryResponse_Item_Action{
+		{Name: ActionCopy, Label: "Copy", Icon: "edit-copy", Default: true},
+		{Name: ActionSave, Label: "Save", Icon: "document-save"},
+	}
+	historyActions = []*pb.QueryResponse_Item_Action{
+		{Name: ActionCopy, Label: "Copy", Icon: "edit-copy", Default: true},
+		{Name: ActionDelete, Label: "Delete", Icon: "edit-delete"},
+	}
+)
+
 type Config struct {
 	common.Config `koanf:",squash"`
 	MaxItems      int    `koanf:"max_items" desc:"max amount of calculation history items" default:"100"`
@@ -53,18 +89,14 @@ var (
 	results     = make(map[uint32]map[string]*pb.QueryResponse_Item)
 )
 
-func init() {
-	config = &Config{
-		Config: common.Config{
-			Icon: "accessories-calculator",
-		},
-		MaxItems:      100,
-		Placeholder:   "calculating...",
-		RequireNumber: true,
-		MinChars:      3,
+func (plugin) Setup() error {
+	c := defaultConfig()
+
+	if err := common.ReadConfig(Name, c); err != nil {
+		return err
 	}
 
-	common.LoadConfig(Name, config)
+	config.Store(c)
 
 	loadHist()
 
@@ -78,21 +110,51 @@ func init() {
 			cmd.Wait()
 		}()
 	}
+
+	return nil
+}
+
+func (plugin) DefaultConfig() any {
+	return defaultConfig()
 }
 
-func PrintDoc() {
+func defaultConfig() *Config {
+	return &Config{
+		Config: common.Config{
+			Icon: "accessories-calculator",
+		},
+		MaxItems:      100,
+		Placeholder:   "calculating...",
+		RequireNumber: true,
+		MinChars:      3,
+	}
+}
+
+func (plugin) Reload() error {
+	c := defaultConfig()
+
+	if err := common.ReadConfig(Name, c); err != nil {
+		return err
+	}
+
+	config.Store(c)
+
+	return nil
+}
+
+func (plugin) PrintDoc() {
 	fmt.Printf("### %s\n", NamePretty)
 	fmt.Println("Calculator/Unit-Conversion with history.")
 	fmt.Println()
 }
 
-func Cleanup(qid uint32) {
+func (plugin) Cleanup(qid uint32) {
 	resultMutex.Lock()
 	delete(results, qid)
 	resultMutex.Unlock()
 }
 
-func Activate(qid uint32, identifier, action string, arguments string) {
+func (p plugin) Activate(qid uint32, identifier, action string, arguments string) error {
 	var item *pb.QueryResponse_Item
 	var result string
 	var createHistoryItem bool
@@ -113,8 +175,7 @@ func Activate(qid uint32, identifier, action string, arguments string) {
 	}
 
 	if result == "" {
-		slog.Error(Name, "activation", "item not found")
-		return
+		return provider.ErrUnknownIdentifier
 	}
 
 	if action == "" {
@@ -127,24 +188,24 @@ func Activate(qid uint32, identifier, action string, arguments string) {
 
 		err := cmd.Start()
 		if err != nil {
-			slog.Error(Name, "actioncopy", err)
-		} else {
-			go func() {
-				cmd.Wait()
-			}()
+			return fmt.Errorf("%w: %w", provider.ErrCommandFailed, err)
 		}
 
+		go func() {
+			cmd.Wait()
+		}()
+
 		if createHistoryItem {
 			saveToHistory(item)
 		}
 
-		Cleanup(qid)
+		p.Cleanup(qid)
 	case ActionSave:
 		if createHistoryItem {
 			saveToHistory(item)
 		}
 
-		Cleanup(qid)
+		p.Cleanup(qid)
 	case ActionDelete:
 		i := 0
 
@@ -162,7 +223,11 @@ func Activate(qid uint32, identifier, action string, arguments string) {
 		}
 
 		saveHist()
+	default:
+		return fmt.Errorf("%w: %s", provider.ErrActionNotSupported, action)
 	}
+
+	return nil
 }
 
 func saveToHistory(item *pb.QueryResponse_Item) {
@@ -177,7 +242,9 @@ func saveToHistory(item *pb.QueryResponse_Item) {
 	saveHist()
 }
 
-func Query(qid uint32, iid uint32, query string, single bool, _ bool) []*pb.QueryResponse_Item {
+func (plugin) Query(ctx context.Context, qid uint32, iid uint32, query string, single bool, _ bool) []*pb.QueryResponse_Item {
+	cfg := config.Load()
+
 	start := time.Now()
 
 	if _, ok := results[qid]; !ok {
@@ -188,7 +255,7 @@ func Query(qid uint32, iid uint32, query string, single bool, _ bool) []*pb.Quer
 
 	hasNumber := true
 
-	if config.RequireNumber {
+	if cfg.RequireNumber {
 		hasNumber = false
 
 		for _, c := range query {
@@ -198,24 +265,29 @@ func Query(qid uint32, iid uint32, query string, single bool, _ bool) []*pb.Quer
 		}
 	}
 
-	if query != "" && len(query) >= config.MinChars && hasNumber {
+	if query != "" && len(query) >= cfg.MinChars && hasNumber {
 		md5 := md5.Sum([]byte(query))
 		md5str := hex.EncodeToString(md5[:])
 
 		e := &pb.QueryResponse_Item{
 			Identifier: md5str,
-			Text:       config.Placeholder,
-			Icon:       config.Icon,
+			Text:       cfg.Placeholder,
+			Icon:       cfg.Icon,
 			Subtext:    query,
 			Provider:   Name,
-			Score:      int32(config.MaxItems) + 1,
+			Score:      int32(cfg.MaxItems) + 1,
 			Type:       pb.QueryResponse_REGULAR,
+			Actions:    resultActions,
 		}
 
 		go func() {
-			cmd := exec.Command("qalc", "-t", query)
+			cmd := exec.CommandContext(ctx, "qalc", "-t", query)
 			out, err := cmd.CombinedOutput()
 
+			if ctx.Err() != nil {
+				return
+			}
+
 			if err == nil {
 				e.Text = strings.TrimSpace(string(out))
 
@@ -237,11 +309,12 @@ func Query(qid uint32, iid uint32, query string, single bool, _ bool) []*pb.Quer
 			e := &pb.QueryResponse_Item{
 				Identifier: v.Identifier,
 				Text:       v.Result,
-				Score:      int32(config.MaxItems - k),
-				Icon:       config.Icon,
+				Score:      int32(cfg.MaxItems - k),
+				Icon:       cfg.Icon,
 				Subtext:    v.Input,
 				Provider:   Name,
 				Type:       pb.QueryResponse_REGULAR,
+				Actions:    historyActions,
 			}
 
 			entries = append(entries, e)
@@ -253,50 +326,27 @@ func Query(qid uint32, iid uint32, query string, single bool, _ bool) []*pb.Quer
 	return entries
 }
 
-func loadHist() {
-	file := common.CacheFile(fmt.Sprintf("%s.gob", Name))
-
-	if common.FileExists(file) {
-		f, err := os.ReadFile(file)
-		if err != nil {
-			slog.Error(Name, "history", err)
-		} else {
-			decoder := gob.NewDecoder(bytes.NewReader(f))
+// storeVersion is the schema version of the stored history.
+const storeVersion = 1
 
-			err = decoder.Decode(&history)
-			if err != nil {
-				slog.Error(Name, "decoding", err)
-			}
-		}
+func loadHist() {
+	if _, err := store.Load(common.CacheFile(fmt.Sprintf("%s.gob", Name)), &history); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.Error(Name, "history", err)
 	}
 }
 
 func saveHist() {
-	if len(history) > config.MaxItems {
-		history = history[:config.MaxItems]
-	}
-
-	var b bytes.Buffer
-	encoder := gob.NewEncoder(&b)
-
-	err := encoder.Encode(history)
-	if err != nil {
-		slog.Error("history", "encode", err)
-		return
-	}
+	cfg := config.Load()
 
-	err = os.MkdirAll(filepath.Dir(common.CacheFile(fmt.Sprintf("%s.gob", Name))), 0755)
-	if err != nil {
-		slog.Error("history", "createdirs", err)
-		return
+	if len(history) > cfg.MaxItems {
+		history = history[:cfg.MaxItems]
 	}
 
-	err = os.WriteFile(common.CacheFile(fmt.Sprintf("%s.gob", Name)), b.Bytes(), 0o600)
-	if err != nil {
-		slog.Error("history", "writefile", err)
+	if err := store.Save(common.CacheFile(fmt.Sprintf("%s.gob", Name)), storeVersion, history); err != nil {
+		slog.Error(Name, "history", err)
 	}
 }
 
-func Icon() string {
-	return config.Icon
+func (plugin) Icon() string {
+	return config.Load().Icon
 }
@@ -0,0 +1,250 @@
+package clipboard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrEmpty is returned by backends if nothing is copied.
+var ErrEmpty = errors.New("nothing is copied")
+
+// Offer is content offered with a mimetype.
+type Offer struct {
+	Mimetype string
+	Content  []byte
+}
+
+// Selections a backend can be created for.
+const (
+	SourceClipboard = "clipboard"
+	SourcePrimary   = "primary"
+)
+
+// ClipboardBackend reads, writes and watches a selection of a display server.
+type ClipboardBackend interface {
+	// Watch calls fn on every change of the clipboard until Close is called. state is "sensitive" if the source
+	// asked for the content not to be stored, if the backend supports it.
+	Watch(fn func(state string)) error
+	// Types returns the mimetypes offered for the current content.
+	Types() ([]string, error)
+	// Read returns the content for the mimetype, text if mimetype is empty.
+	Read(mimetype string) ([]byte, error)
+	// Write copies the content, offered with all given mimetypes. The content is served by elephant until another
+	// client copies something, backends falling back to a copy tool offer the first offer only.
+	Write(offers []Offer) error
+	// Close stops watching.
+	Close()
+}
+
+var (
+	backend        ClipboardBackend
+	primaryBackend ClipboardBackend
+)
+
+// backendFor returns the backend of the selection.
+func backendFor(source string) ClipboardBackend {
+	if source == SourcePrimary {
+		return primaryBackend
+	}
+
+	return backend
+}
+
+// newBackend returns the backend by name for the selection, "auto" selects it by the session type.
+func newBackend(name, selection string) (ClipboardBackend, error) {
+	if name == "" || name == "auto" {
+		name = detectBackend()
+		slog.Info(Name, "backend", name, "selection", selection)
+	}
+
+	switch name {
+	case "wayland":
+		return &waylandBackend{primary: selection == SourcePrimary}, nil
+	case "xclip", "xsel":
+		return &x11Backend{tool: name, selection: selection, done: make(chan struct{})}, nil
+	case "memory":
+		return newMemoryBackend(), nil
+	default:
+		return nil, fmt.Errorf("unknown backend '%s', available: auto, wayland, xclip, xsel, memory", name)
+	}
+}
+
+func detectBackend() string {
+	if os.Getenv("WAYLAND_DISPLAY") != "" || os.Getenv("XDG_SESSION_TYPE") == "wayland" {
+		return "wayland"
+	}
+
+	if os.Getenv("DISPLAY") != "" || os.Getenv("XDG_SESSION_TYPE") == "x11" {
+		if _, err := exec.LookPath("xclip"); err == nil {
+			return "xclip"
+		}
+
+		if _, err := exec.LookPath("xsel"); err == nil {
+			return "xsel"
+		}
+	}
+
+	// the environment of services is often incomplete, wl-paste might still find the compositor
+	return "wayland"
+}
+
+// textTargets are the names text is offered with in addition to its mimetype, X11 clients often only ask for the
+// legacy targets.
+var textTargets = []string{"text/plain", "text/plain;charset=utf-8", "UTF8_STRING", "STRING", "TEXT"}
+
+// targets returns the names to offer in order and their content, text is offered with all the common names for it.
+func targets(offers []Offer) ([]string, map[string][]byte) {
+	names := []string{}
+	content := make(map[string][]byte)
+
+	add := func(name string, b []byte) {
+		if _, ok := content[name]; !ok {
+			names = append(names, name)
+			content[name] = b
+		}
+	}
+
+	for _, v := range offers {
+		add(v.Mimetype, v.Content)
+	}
+
+	if i := slices.IndexFunc(offers, func(v Offer) bool { return strings.HasPrefix(v.Mimetype, "text/plain") }); i != -1 {
+		for _, v := range textTargets {
+			add(v, offers[i].Content)
+		}
+	}
+
+	return names, content
+}
+
+// runInBackground runs cmd with content as stdin without waiting for it, copy tools keep running to serve the
+// content.
+func runInBackground(cmd *exec.Cmd, content []byte) error {
+	cmd.Stdin = bytes.NewReader(content)
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	go func() {
+		cmd.Wait()
+	}()
+
+	return nil
+}
+
+func commandError(err error, stderr string) error {
+	if msg := strings.TrimSpace(stderr); msg != "" {
+		return fmt.Errorf("%w: %s", err, msg)
+	}
+
+	return err
+}
+
+// memoryBackend keeps the clipboard in memory, so the history can be used without a display server, f.e. in tests.
+type memoryBackend struct {
+	mu      sync.Mutex
+	types   []string
+	content map[string][]byte
+	// changes is nil if the selection isn't watched
+	changes chan string
+	done    chan struct{}
+	once    sync.Once
+}
+
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{
+		content: make(map[string][]byte),
+		changes: make(chan string, 16),
+		done:    make(chan struct{}),
+	}
+}
+
+func (b *memoryBackend) Watch(fn func(state string)) error {
+	for {
+		select {
+		case state := <-b.changes:
+			fn(state)
+		case <-b.done:
+			return nil
+		}
+	}
+}
+
+func (b *memoryBackend) Types() ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.types) == 0 {
+		return nil, ErrEmpty
+	}
+
+	return slices.Clone(b.types), nil
+}
+
+func (b *memoryBackend) Read(mimetype string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.types) == 0 {
+		return nil, ErrEmpty
+	}
+
+	if mimetype == "" {
+		mimetype = b.types[0]
+
+		if i := slices.IndexFunc(b.types, func(v string) bool { return strings.HasPrefix(v, "text/plain") }); i != -1 {
+			mimetype = b.types[i]
+		} else if i := slices.IndexFunc(b.types, func(v string) bool { return strings.HasPrefix(v, "text/") }); i != -1 {
+			mimetype = b.types[i]
+		}
+	}
+
+	content, ok := b.content[mimetype]
+	if !ok {
+		return nil, fmt.Errorf("mimetype '%s' not offered", mimetype)
+	}
+
+	return content, nil
+}
+
+func (b *memoryBackend) Write(offers []Offer) error {
+	b.Set("", offers...)
+
+	return nil
+}
+
+// Set replaces the content with the offers and notifies the watcher, if there is one.
+func (b *memoryBackend) Set(state string, offers ...Offer) {
+	b.mu.Lock()
+	b.types = []string{}
+	b.content = make(map[string][]byte)
+
+	for _, v := range offers {
+		b.types = append(b.types, v.Mimetype)
+		b.content[v.Mimetype] = v.Content
+	}
+	b.mu.Unlock()
+
+	if b.changes == nil {
+		return
+	}
+
+	select {
+	case b.changes <- state:
+	case <-b.done:
+	}
+}
+
+func (b *memoryBackend) Close() {
+	b.once.Do(func() {
+		close(b.done)
+	})
+}
@@ -0,0 +1,32 @@
+package clipboard
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTargets(t *testing.T) {
+	offers := []Offer{
+		{Mimetype: "text/html", Content: []byte("<b>a</b>")},
+		{Mimetype: "text/plain;charset=utf-8", Content: []byte("a")},
+	}
+
+	names, content := targets(offers)
+
+	want := []string{"text/html", "text/plain;charset=utf-8", "text/plain", "UTF8_STRING", "STRING", "TEXT"}
+	if !slices.Equal(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+
+	if string(content["text/html"]) != "<b>a</b>" || string(content["UTF8_STRING"]) != "a" {
+		t.Errorf("got %q", content)
+	}
+}
+
+func TestTargetsWithoutText(t *testing.T) {
+	names, _ := targets([]Offer{{Mimetype: "image/png", Content: []byte{1}}})
+
+	if !slices.Equal(names, []string{"image/png"}) {
+		t.Errorf("got %v", names)
+	}
+}
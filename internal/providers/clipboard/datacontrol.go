@@ -0,0 +1,412 @@
+package clipboard
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// errNoDataControl is returned if the compositor doesn't support any of the data control protocols, f.e. GNOME.
+var errNoDataControl = errors.New("compositor doesn't support data control")
+
+// errCancelled stops serving a data source, another client took the selection.
+var errCancelled = errors.New("data source cancelled")
+
+// dataControls are the clipboard manager protocols by preference and the highest version used. The version 2 of
+// the wlroots protocol adds the primary selection.
+var dataControls = []struct {
+	name    string
+	version uint32
+}{
+	{"ext_data_control_manager_v1", 1},
+	{"zwlr_data_control_manager_v1", 2},
+}
+
+// Object ids and opcodes used of the core and the data control protocols, the data control ones are the same for
+// both protocols.
+const (
+	wlDisplay = 1
+
+	wlDisplaySync        = 0
+	wlDisplayGetRegistry = 1
+	wlDisplayError       = 0
+
+	wlRegistryBind   = 0
+	wlRegistryGlobal = 0
+
+	dataControlCreateDataSource = 0
+	dataControlGetDataDevice    = 1
+
+	dataDeviceSetSelection        = 0
+	dataDeviceSetPrimarySelection = 2
+	dataDeviceFinished            = 2
+
+	dataSourceOffer     = 0
+	dataSourceSend      = 0
+	dataSourceCancelled = 1
+)
+
+// wlConn is a minimal client of the Wayland wire protocol, just enough to offer content with a data control
+// protocol.
+type wlConn struct {
+	conn *net.UnixConn
+	last uint32
+	buf  []byte
+	fds  []int
+	err  error
+}
+
+type wlEvent struct {
+	sender uint32
+	opcode uint16
+	data   []byte
+}
+
+// dialWayland connects to the compositor like libwayland does.
+func dialWayland() (*wlConn, error) {
+	socket := os.Getenv("WAYLAND_DISPLAY")
+	if socket == "" {
+		socket = "wayland-0"
+	}
+
+	if !filepath.IsAbs(socket) {
+		socket = filepath.Join(os.Getenv("XDG_RUNTIME_DIR"), socket)
+	}
+
+	conn, err := net.DialUnix("unix", nil, &net.UnixAddr{Name: socket, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+
+	return &wlConn{conn: conn, last: wlDisplay}, nil
+}
+
+func (c *wlConn) newID() uint32 {
+	c.last++
+	return c.last
+}
+
+// request sends a request with uint32 or string arguments. Errors are kept and returned by roundtrip, so requests
+// can be sent without checking each of them.
+func (c *wlConn) request(object uint32, opcode uint16, args ...any) {
+	if c.err != nil {
+		return
+	}
+
+	body := []byte{}
+
+	for _, v := range args {
+		switch v := v.(type) {
+		case uint32:
+			body = binary.NativeEndian.AppendUint32(body, v)
+		case string:
+			// null terminated and padded to 32 bits
+			body = binary.NativeEndian.AppendUint32(body, uint32(len(v)+1))
+			body = append(body, v...)
+			body = append(body, make([]byte, 4-len(v)%4)...)
+		default:
+			panic(fmt.Sprintf("unsupported argument %T", v))
+		}
+	}
+
+	msg := binary.NativeEndian.AppendUint32(nil, object)
+	msg = binary.NativeEndian.AppendUint32(msg, uint32(8+len(body))<<16|uint32(opcode))
+
+	_, c.err = c.conn.Write(append(msg, body...))
+}
+
+// event returns the next event. Errors of the display are returned as error.
+func (c *wlConn) event() (wlEvent, error) {
+	for {
+		for len(c.buf) < 8 || len(c.buf) < int(binary.NativeEndian.Uint32(c.buf[4:])>>16) {
+			buf := make([]byte, 4096)
+			oob := make([]byte, unix.CmsgSpace(28*4))
+
+			n, oobn, _, _, err := c.conn.ReadMsgUnix(buf, oob)
+			if err != nil {
+				return wlEvent{}, err
+			}
+
+			if n == 0 && oobn == 0 {
+				return wlEvent{}, io.EOF
+			}
+
+			if err := c.receiveFds(oob[:oobn]); err != nil {
+				return wlEvent{}, err
+			}
+
+			c.buf = append(c.buf, buf[:n]...)
+		}
+
+		header := binary.NativeEndian.Uint32(c.buf[4:])
+
+		size := int(header >> 16)
+		if size < 8 {
+			return wlEvent{}, fmt.Errorf("invalid message size %d", size)
+		}
+
+		e := wlEvent{
+			sender: binary.NativeEndian.Uint32(c.buf),
+			opcode: uint16(header),
+			data:   c.buf[8:size],
+		}
+
+		c.buf = c.buf[size:]
+
+		// besides errors the display only confirms deleted ids
+		if e.sender != wlDisplay {
+			return e, nil
+		}
+
+		if e.opcode == wlDisplayError {
+			object, code, msg := e.uint(), e.uint(), e.string()
+			return wlEvent{}, fmt.Errorf("wayland error %d on object %d: %s", code, object, msg)
+		}
+	}
+}
+
+func (c *wlConn) receiveFds(oob []byte) error {
+	msgs, err := unix.ParseSocketControlMessage(oob)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range msgs {
+		fds, err := unix.ParseUnixRights(&v)
+		if err != nil {
+			return err
+		}
+
+		c.fds = append(c.fds, fds...)
+	}
+
+	return nil
+}
+
+// fd returns the next file descriptor received.
+func (c *wlConn) fd() (int, error) {
+	if len(c.fds) == 0 {
+		return -1, errors.New("missing file descriptor")
+	}
+
+	fd := c.fds[0]
+	c.fds = c.fds[1:]
+
+	return fd, nil
+}
+
+// roundtrip waits until the compositor processed all requests sent so far, other events are passed to handle.
+func (c *wlConn) roundtrip(handle func(e *wlEvent) error) error {
+	callback := c.newID()
+	c.request(wlDisplay, wlDisplaySync, callback)
+
+	if c.err != nil {
+		return c.err
+	}
+
+	for {
+		e, err := c.event()
+		if err != nil {
+			return err
+		}
+
+		if e.sender == callback {
+			return nil
+		}
+
+		if err := handle(&e); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *wlConn) Close() {
+	c.conn.Close()
+
+	for _, v := range c.fds {
+		unix.Close(v)
+	}
+}
+
+func (e *wlEvent) uint() uint32 {
+	if len(e.data) < 4 {
+		return 0
+	}
+
+	v := binary.NativeEndian.Uint32(e.data)
+	e.data = e.data[4:]
+
+	return v
+}
+
+func (e *wlEvent) string() string {
+	n := int(e.uint())
+	if n == 0 || n > len(e.data) {
+		return ""
+	}
+
+	v := string(e.data[:n-1])
+	e.data = e.data[min((n+3)&^3, len(e.data)):]
+
+	return v
+}
+
+// offer takes the selection with a data source offering all offers, which is served until another client takes the
+// selection.
+func (b *waylandBackend) offer(offers []Offer) error {
+	c, err := dialWayland()
+	if err != nil {
+		return err
+	}
+
+	serve, err := b.setSelection(c, offers)
+	if err != nil {
+		c.Close()
+
+		if errors.Is(err, errCancelled) {
+			return nil
+		}
+
+		return err
+	}
+
+	go func() {
+		defer c.Close()
+
+		for {
+			e, err := c.event()
+			if err == nil {
+				err = serve(&e)
+			}
+
+			if err != nil {
+				if !errors.Is(err, errCancelled) {
+					slog.Error(Name, "wayland", err)
+				}
+
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// setSelection creates the data source and sets it as selection. The returned function serves the events of the
+// data source.
+func (b *waylandBackend) setSelection(c *wlConn, offers []Offer) (func(e *wlEvent) error, error) {
+	type global struct {
+		name    uint32
+		iface   string
+		version uint32
+	}
+
+	var seat, manager *global
+
+	registry := c.newID()
+	c.request(wlDisplay, wlDisplayGetRegistry, registry)
+
+	err := c.roundtrip(func(e *wlEvent) error {
+		if e.sender != registry || e.opcode != wlRegistryGlobal {
+			return nil
+		}
+
+		g := &global{name: e.uint(), iface: e.string(), version: e.uint()}
+
+		switch {
+		case g.iface == "wl_seat":
+			if seat == nil {
+				seat = g
+			}
+		case g.iface == dataControls[0].name:
+			manager = g
+		case g.iface == dataControls[1].name:
+			if manager == nil {
+				manager = g
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if seat == nil || manager == nil {
+		return nil, errNoDataControl
+	}
+
+	version := manager.version
+
+	for _, v := range dataControls {
+		if v.name == manager.iface {
+			version = min(version, v.version)
+		}
+	}
+
+	if b.primary && manager.iface == dataControls[1].name && version < 2 {
+		return nil, errNoDataControl
+	}
+
+	seatID, managerID, source, device := c.newID(), c.newID(), c.newID(), c.newID()
+
+	c.request(registry, wlRegistryBind, seat.name, seat.iface, uint32(1), seatID)
+	c.request(registry, wlRegistryBind, manager.name, manager.iface, version, managerID)
+	c.request(managerID, dataControlCreateDataSource, source)
+
+	names, content := targets(offers)
+
+	for _, v := range names {
+		c.request(source, dataSourceOffer, v)
+	}
+
+	c.request(managerID, dataControlGetDataDevice, device, seatID)
+
+	if b.primary {
+		c.request(device, dataDeviceSetPrimarySelection, source)
+	} else {
+		c.request(device, dataDeviceSetSelection, source)
+	}
+
+	serve := func(e *wlEvent) error {
+		switch {
+		case e.sender == source && e.opcode == dataSourceSend:
+			mimetype := e.string()
+
+			fd, err := c.fd()
+			if err != nil {
+				return err
+			}
+
+			go writeFd(fd, content[mimetype])
+		case e.sender == source && e.opcode == dataSourceCancelled:
+			return errCancelled
+		case e.sender == device && e.opcode == dataDeviceFinished:
+			return errCancelled
+		}
+
+		return nil
+	}
+
+	// protocol errors are reported by the roundtrip, clients might already ask for the content
+	if err := c.roundtrip(serve); err != nil {
+		return nil, err
+	}
+
+	return serve, nil
+}
+
+// writeFd writes the content to the pipe of the client pasting it.
+func writeFd(fd int, content []byte) {
+	f := os.NewFile(uintptr(fd), "selection")
+	defer f.Close()
+
+	f.Write(content)
+}
@@ -0,0 +1,179 @@
+package clipboard
+
+import (
+	"encoding/binary"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// testConn returns a client connection and the compositor end of it.
+func testConn(t *testing.T) (*wlConn, *net.UnixConn) {
+	t.Helper()
+
+	fds, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_STREAM, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conns := []*net.UnixConn{}
+
+	for _, fd := range fds {
+		f := os.NewFile(uintptr(fd), "wayland")
+
+		c, err := net.FileConn(f)
+		f.Close()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		conns = append(conns, c.(*net.UnixConn))
+	}
+
+	c := &wlConn{conn: conns[0], last: wlDisplay}
+
+	t.Cleanup(func() {
+		c.Close()
+		conns[1].Close()
+	})
+
+	return c, conns[1]
+}
+
+// message encodes a message like the compositor does.
+func message(sender uint32, opcode uint16, body []byte) []byte {
+	b := binary.NativeEndian.AppendUint32(nil, sender)
+	b = binary.NativeEndian.AppendUint32(b, uint32(8+len(body))<<16|uint32(opcode))
+
+	return append(b, body...)
+}
+
+// wlString encodes a string argument.
+func wlString(v string) []byte {
+	b := binary.NativeEndian.AppendUint32(nil, uint32(len(v)+1))
+	b = append(b, v...)
+
+	return append(b, make([]byte, (len(v)+4)&^3-len(v))...)
+}
+
+func TestRequestPadsStrings(t *testing.T) {
+	c, server := testConn(t)
+
+	for _, v := range []string{"", "a", "abc", "abcd", "text/plain"} {
+		c.request(3, 1, v, uint32(7))
+
+		if c.err != nil {
+			t.Fatal(c.err)
+		}
+
+		// header, length, string with terminator padded to 32 bits, uint
+		size := 8 + 4 + (len(v)+4)&^3 + 4
+
+		b := make([]byte, size)
+		if _, err := server.Read(b); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := int(binary.NativeEndian.Uint32(b[4:]) >> 16); got != size {
+			t.Errorf("%q: got size %d, want %d", v, got, size)
+		}
+
+		e := wlEvent{data: b[8:]}
+
+		if got := e.string(); got != v {
+			t.Errorf("got %q, want %q", got, v)
+		}
+
+		if got := e.uint(); got != 7 {
+			t.Errorf("%q: got %d after the string, want 7", v, got)
+		}
+	}
+}
+
+func TestEvent(t *testing.T) {
+	c, server := testConn(t)
+
+	body := append(binary.NativeEndian.AppendUint32(nil, 5), wlString("wl_seat")...)
+	body = binary.NativeEndian.AppendUint32(body, 8)
+
+	// a deleted id confirmation of the display is skipped, the event is split over two writes
+	msg := append(message(wlDisplay, 1, binary.NativeEndian.AppendUint32(nil, 3)), message(2, wlRegistryGlobal, body)...)
+
+	go func() {
+		server.Write(msg[:13])
+		server.Write(msg[13:])
+	}()
+
+	e, err := c.event()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if e.sender != 2 || e.opcode != wlRegistryGlobal {
+		t.Fatalf("got sender %d, opcode %d", e.sender, e.opcode)
+	}
+
+	if name, iface, version := e.uint(), e.string(), e.uint(); name != 5 || iface != "wl_seat" || version != 8 {
+		t.Errorf("got %d, %q, %d", name, iface, version)
+	}
+}
+
+func TestEventDisplayError(t *testing.T) {
+	c, server := testConn(t)
+
+	body := binary.NativeEndian.AppendUint32(nil, 4)
+	body = binary.NativeEndian.AppendUint32(body, 1)
+	body = append(body, wlString("invalid source")...)
+
+	server.Write(message(wlDisplay, wlDisplayError, body))
+
+	if _, err := c.event(); err == nil || !strings.Contains(err.Error(), "invalid source") {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestEventFd(t *testing.T) {
+	c, server := testConn(t)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer r.Close()
+
+	msg := message(3, dataSourceSend, wlString("text/plain"))
+
+	if _, _, err := server.WriteMsgUnix(msg, unix.UnixRights(int(w.Fd())), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	w.Close()
+
+	e, err := c.event()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := e.string(); got != "text/plain" {
+		t.Errorf("got %q", got)
+	}
+
+	fd, err := c.fd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeFd(fd, []byte("content"))
+
+	b := make([]byte, 16)
+
+	n, _ := r.Read(b)
+	if string(b[:n]) != "content" {
+		t.Errorf("got %q", b[:n])
+	}
+}
@@ -0,0 +1,121 @@
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"slices"
+	"time"
+)
+
+// defaultIgnorePatterns match private keys, common api tokens and text consisting of a credit card number.
+var defaultIgnorePatterns = []string{
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----`,
+	`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,}|glpat-[A-Za-z0-9_-]{20,}|xox[abpr]-[A-Za-z0-9-]{10,}|AKIA[0-9A-Z]{16}|sk-[A-Za-z0-9_-]{20,})\b`,
+	`^\s*(?:\d[ -]?){12,18}\d\s*$`,
+}
+
+var ignorePatterns []*regexp.Regexp
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := []*regexp.Regexp{}
+	errs := []error{}
+
+	for _, v := range patterns {
+		r, err := regexp.Compile(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("ignore_patterns: %w", err))
+			continue
+		}
+
+		res = append(res, r)
+	}
+
+	return res, errors.Join(errs...)
+}
+
+// setupPatterns compiles the configured patterns, skipping invalid ones. Sensitive content must not end up in the
+// history because of a typo, so the defaults are used if none of the patterns is valid.
+func setupPatterns(patterns []string) []*regexp.Regexp {
+	res, err := compilePatterns(patterns)
+	if err == nil {
+		return res
+	}
+
+	slog.Error(Name, "config", err)
+
+	if len(res) == 0 {
+		res, _ = compilePatterns(defaultIgnorePatterns)
+	}
+
+	return res
+}
+
+// isSensitive checks if the content must not be stored permanently. state is the CLIPBOARD_STATE reported by
+// wl-paste, which is "sensitive" if the source asked for it.
+func isSensitive(state string, mimetypes []string, content []byte, text bool) bool {
+	if state == "sensitive" {
+		return true
+	}
+
+	for _, v := range mimetypes {
+		if slices.Contains(config.IgnoreMimetypes, v) {
+			return true
+		}
+	}
+
+	if !text {
+		return false
+	}
+
+	for _, v := range ignorePatterns {
+		if v.Match(content) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scheduleExpiry removes expired items once t has passed.
+func scheduleExpiry(t time.Time) {
+	time.AfterFunc(time.Until(t), func() {
+		historyMu.Lock()
+		defer historyMu.Unlock()
+
+		if removeExpired() {
+			saveToFile()
+		}
+	})
+}
+
+// removeExpired removes sensitive items whose ttl has passed, pinned items are kept. The caller has to hold
+// historyMu.
+func removeExpired() bool {
+	now := time.Now()
+	removed := false
+
+	for k, v := range history {
+		if !v.Pinned && !v.Expires.IsZero() && now.After(v.Expires) {
+			deleteItem(k)
+			removed = true
+		}
+	}
+
+	if removed {
+		slog.Info(Name, "expired", "sensitive")
+	}
+
+	return removed
+}
+
+// deleteItem removes the item and its image. The caller has to hold historyMu.
+func deleteItem(identifier string) {
+	if history[identifier].Img != "" {
+		_ = os.Remove(history[identifier].Img)
+	}
+
+	delete(history, identifier)
+}
@@ -0,0 +1,149 @@
+package clipboard
+
+import (
+	"bytes"
+	"log/slog"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+)
+
+const uriList = "text/uri-list"
+
+// primaryType returns the mimetype describing the content best: images, copied files, then text.
+func primaryType(types []string) string {
+	for _, v := range types {
+		if _, ok := imgTypes[v]; ok {
+			return v
+		}
+	}
+
+	if slices.Contains(types, uriList) {
+		return uriList
+	}
+
+	for _, v := range types {
+		if strings.HasPrefix(v, "text/plain") {
+			return v
+		}
+	}
+
+	if len(types) > 0 {
+		return types[0]
+	}
+
+	return "text/plain"
+}
+
+// readOffers reads the payloads of all offered mimetypes, besides images stored as file. Payloads equal to the text
+// content aren't stored again, additional mimetypes with payloads bigger than max_type_size are dropped. Returns the
+// mimetypes that can be restored and the payloads to store.
+func readOffers(b ClipboardBackend, types []string, primary, img string, content []byte, maxTypeSize int) ([]string, map[string][]byte) {
+	kept := []string{}
+	offers := make(map[string][]byte)
+
+	for _, v := range types {
+		if v == img {
+			kept = append(kept, v)
+			continue
+		}
+
+		if v != primary && maxTypeSize <= 0 {
+			continue
+		}
+
+		payload, err := b.Read(v)
+		if err != nil {
+			slog.Debug(Name, "mimetype", v, "error", err)
+			continue
+		}
+
+		if v != primary && len(payload) > maxTypeSize {
+			slog.Debug(Name, "mimetype", v, "dropped", "max_type_size", "size", len(payload))
+			continue
+		}
+
+		kept = append(kept, v)
+
+		if img != "" || !bytes.Equal(payload, content) {
+			offers[v] = payload
+		}
+	}
+
+	return kept, offers
+}
+
+// primary returns the primary mimetype, items stored before all mimetypes were kept only have it set for images.
+func (i Item) primary() string {
+	if i.Mimetype == "" {
+		return "text/plain"
+	}
+
+	return i.Mimetype
+}
+
+// offers returns everything needed to restore the item, starting with its primary mimetype.
+func (i Item) offers() ([]Offer, error) {
+	primary := i.primary()
+
+	content := []byte(i.Content)
+
+	if i.Img != "" {
+		var err error
+
+		content, err = os.ReadFile(i.Img)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// offers of text items are only stored if they differ from the text
+	payload := func(mimetype string) []byte {
+		if b, ok := i.Offers[mimetype]; ok || i.Img != "" && mimetype != primary {
+			return b
+		}
+
+		return content
+	}
+
+	res := []Offer{{Mimetype: primary, Content: payload(primary)}}
+
+	for _, v := range i.Types {
+		if v != primary {
+			res = append(res, Offer{Mimetype: v, Content: payload(v)})
+		}
+	}
+
+	return res, nil
+}
+
+// Preview returns the image of images, the first local file of copied files and the text otherwise.
+func (plugin) Preview(identifier string) string {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	item, ok := history[identifier]
+	if !ok {
+		return ""
+	}
+
+	if item.Img != "" {
+		return item.Img
+	}
+
+	if item.Mimetype == uriList {
+		list, ok := item.Offers[uriList]
+		if !ok {
+			list = []byte(item.Content)
+		}
+
+		for v := range strings.Lines(string(list)) {
+			if u, err := url.Parse(strings.TrimSpace(v)); err == nil && u.Scheme == "file" {
+				return u.Path
+			}
+		}
+	}
+
+	return item.Content
+}
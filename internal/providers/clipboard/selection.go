@@ -0,0 +1,35 @@
+package clipboard
+
+import (
+	"log/slog"
+	"slices"
+)
+
+// watch adds changes of the selection to the history until the backend is closed.
+func watch(source string) {
+	err := backendFor(source).Watch(func(state string) {
+		update(source, state)
+	})
+	if err != nil {
+		slog.Error(Name, "watch", err, "selection", source)
+	}
+}
+
+// sources returns the selections the item was copied from, older items are from the clipboard.
+func (i Item) sources() []string {
+	if len(i.Sources) == 0 {
+		return []string{SourceClipboard}
+	}
+
+	return i.Sources
+}
+
+// limitedBy returns the selection whose max_items the item counts towards. Items copied to both selections count as
+// clipboard items, so tracking the primary selection doesn't evict them.
+func (i Item) limitedBy() string {
+	if slices.Contains(i.sources(), SourceClipboard) {
+		return SourceClipboard
+	}
+
+	return SourcePrimary
+}
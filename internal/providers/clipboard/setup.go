@@ -1,25 +1,26 @@
 // Package clipboard provides access to the clipboard history.
-package main
+package clipboard
 
 import (
-	"bufio"
-	"bytes"
+	"context"
 	"crypto/md5"
-	"encoding/gob"
 	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
+	"io/fs"
 	"log/slog"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/abenz1267/elephant/internal/common"
+	"github.com/abenz1267/elephant/internal/common/store"
 	"github.com/abenz1267/elephant/internal/util"
 	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"github.com/abenz1267/elephant/pkg/provider"
 )
 
 var (
@@ -29,32 +30,72 @@ var (
 	imgTypes   = make(map[string]string)
 	config     *Config
 	history    map[string]Item
+	historyMu  sync.Mutex
 )
 
+type plugin struct{}
+
+// Provider is looked up by elephant when loading the plugin.
+var Provider provider.Provider = plugin{}
+
+func (plugin) APIVersion() int {
+	return provider.APIVersion
+}
+
+func (plugin) Name() string {
+	return Name
+}
+
+func (plugin) NamePretty() string {
+	return NamePretty
+}
+
 type Item struct {
-	Content  string
-	Img      string
+	Content string
+	Img     string
+	// Mimetype is the primary mimetype of the content
 	Mimetype string
 	Time     time.Time
+	// Types are all mimetypes restored on copy, Offers holds their payloads if they differ from Content
+	Types  []string
+	Offers map[string][]byte
+	// Pinned items are never trimmed and listed first
+	Pinned bool
+	// Expires is set for sensitive items, which are removed afterwards
+	Expires time.Time
+	// Sources are the selections the content was copied from, empty for items stored before the primary selection
+	// was tracked
+	Sources []string
 }
 
 type Config struct {
-	common.Config `koanf:",squash"`
-	MaxItems      int `koanf:"max_items" desc:"max amount of clipboard history items" default:"100"`
+	common.Config   `koanf:",squash"`
+	MaxItems        int           `koanf:"max_items" desc:"max amount of clipboard history items, pinned items don't count" default:"100"`
+	MaxSize         int           `koanf:"max_size" desc:"max size of an item in bytes, bigger content isn't stored. 0 for no limit" default:"0"`
+	MaxTypeSize     int           `koanf:"max_type_size" desc:"max size in bytes of each additionally offered mimetype, f.e. text/html. bigger ones aren't stored. 0 to only store the primary mimetype" default:"262144"`
+	IgnorePatterns  []string      `koanf:"ignore_patterns" desc:"regular expressions, text matching one of them is sensitive. replaces the defaults" default:"private keys, api tokens, credit card numbers"`
+	IgnoreMimetypes []string      `koanf:"ignore_mimetypes" desc:"content offering one of these mimetypes is sensitive, f.e. set by password managers" default:"x-kde-passwordManagerHint"`
+	SensitiveTTL    time.Duration `koanf:"sensitive_ttl" desc:"keep sensitive content for this long instead of not storing it at all" default:"0s"`
+	Backend         string        `koanf:"backend" desc:"clipboard backend: auto, wayland, xclip, xsel or memory. changes require a restart" default:"auto"`
+	Primary         PrimaryConfig `koanf:"primary" desc:"history of the primary selection, f.e. text selected with the mouse" default:""`
 }
 
-func init() {
+type PrimaryConfig struct {
+	Enabled  bool `koanf:"enabled" desc:"track the primary selection. changes require a restart" default:"false"`
+	MaxItems int  `koanf:"max_items" desc:"max amount of items only copied from the primary selection, pinned items don't count" default:"50"`
+	Merge    bool `koanf:"merge" desc:"store content copied from both selections as a single entry" default:"true"`
+}
+
+func (plugin) Setup() error {
 	start := time.Now()
 
-	config = &Config{
-		Config: common.Config{
-			Icon:     "user-bookmarks",
-			MinScore: 30,
-		},
-		MaxItems: 100,
+	config = defaultConfig()
+
+	if err := common.ReadConfig(Name, config); err != nil {
+		return err
 	}
 
-	common.LoadConfig(Name, config)
+	ignorePatterns = setupPatterns(config.IgnorePatterns)
 
 	imgTypes["image/png"] = "png"
 	imgTypes["image/jpg"] = "jpg"
@@ -62,152 +103,265 @@ func init() {
 
 	loadFromFile()
 
-	go handleChange()
-
-	slog.Info(Name, "history", len(history), "time", time.Since(start))
-}
-
-func loadFromFile() {
-	if common.FileExists(file) {
-		f, err := os.ReadFile(file)
-		if err != nil {
-			slog.Error("history", "load", err)
-		} else {
-			decoder := gob.NewDecoder(bytes.NewReader(f))
+	removeExpired()
 
-			err = decoder.Decode(&history)
-			if err != nil {
-				slog.Error("history", "decoding", err)
-			}
+	for _, v := range history {
+		if !v.Expires.IsZero() {
+			scheduleExpiry(v.Expires)
 		}
-	} else {
-		history = map[string]Item{}
 	}
-}
 
-func saveToFile() {
-	var b bytes.Buffer
-	encoder := gob.NewEncoder(&b)
+	var err error
 
-	err := encoder.Encode(history)
+	backend, err = newBackend(config.Backend, SourceClipboard)
 	if err != nil {
-		slog.Error(Name, "encode", err)
-		return
+		slog.Error(Name, "backend", err)
+		backend = newMemoryBackend()
 	}
 
-	err = os.MkdirAll(filepath.Dir(file), 0755)
+	// entries can be copied to the primary selection even if it isn't tracked
+	primaryBackend, err = newBackend(config.Backend, SourcePrimary)
 	if err != nil {
-		slog.Error(Name, "createdirs", err)
-		return
+		slog.Error(Name, "backend", err, "selection", SourcePrimary)
+		primaryBackend = newMemoryBackend()
 	}
 
-	err = os.WriteFile(file, b.Bytes(), 0o600)
-	if err != nil {
-		slog.Error(Name, "writefile", err)
+	if m, ok := primaryBackend.(*memoryBackend); ok && !config.Primary.Enabled {
+		// nobody would read the changes
+		m.changes = nil
 	}
+
+	go watch(SourceClipboard)
+
+	if config.Primary.Enabled {
+		go watch(SourcePrimary)
+	}
+
+	slog.Info(Name, "history", len(history), "time", time.Since(start))
+
+	return nil
 }
 
-func handleChange() {
-	cmd := exec.Command("wl-paste", "--watch", "echo", "")
+func (plugin) DefaultConfig() any {
+	return defaultConfig()
+}
 
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		slog.Error(Name, "load", err)
-		os.Exit(1)
+func defaultConfig() *Config {
+	return &Config{
+		Config: common.Config{
+			Icon:     "user-bookmarks",
+			MinScore: 30,
+		},
+		MaxItems:        100,
+		MaxTypeSize:     256 * 1024,
+		Backend:         "auto",
+		IgnorePatterns:  defaultIgnorePatterns,
+		IgnoreMimetypes: []string{"x-kde-passwordManagerHint"},
+		Primary: PrimaryConfig{
+			MaxItems: 50,
+			Merge:    true,
+		},
 	}
+}
+
+func (plugin) Reload() error {
+	c := defaultConfig()
 
-	err = cmd.Start()
+	if err := common.ReadConfig(Name, c); err != nil {
+		return err
+	}
+
+	patterns, err := compilePatterns(c.IgnorePatterns)
 	if err != nil {
-		slog.Error(Name, "load", err)
-		os.Exit(1)
-	} else {
-		go func() {
-			cmd.Wait()
-		}()
+		return err
 	}
 
-	scanner := bufio.NewScanner(stdout)
+	historyMu.Lock()
+	config = c
+	ignorePatterns = patterns
+	historyMu.Unlock()
+
+	return nil
+}
+
+// storeVersion is the schema version of the stored history.
+const storeVersion = 1
+
+func loadFromFile() {
+	history = map[string]Item{}
 
-	for scanner.Scan() {
-		update()
+	if _, err := store.Load(file, &history); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.Error(Name, "load", err)
 	}
 }
 
-func update() {
-	if os.Getenv("CLIPBOARD_STATE") == "sensitive" {
-		return
+func saveToFile() {
+	if err := store.Save(file, storeVersion, history); err != nil {
+		slog.Error(Name, "save", err)
 	}
+}
+
+func update(source, state string) {
+	b := backendFor(source)
 
-	cmd := exec.Command("wl-paste", "-n")
-	out, err := cmd.CombinedOutput()
+	mt, err := b.Types()
 	if err != nil {
-		if strings.Contains(string(out), "Nothing is copied") {
-			return
+		if !errors.Is(err, ErrEmpty) {
+			slog.Error(Name, "types", err)
 		}
 
-		slog.Error("clipboard", "error", err)
-
 		return
 	}
 
-	mt := getMimetypes()
-	isImg := false
+	// images are stored as file, everything else as text
+	primary := primaryType(mt)
+
+	img := ""
+	if _, ok := imgTypes[primary]; ok {
+		img = primary
+	}
+
+	isImg := img != ""
 
-	for _, v := range mt {
-		if _, ok := imgTypes[v]; ok {
-			isImg = true
-			break
+	out, err := b.Read(img)
+	if err != nil {
+		if !errors.Is(err, ErrEmpty) {
+			slog.Error(Name, "read", err)
 		}
+
+		return
 	}
 
 	md5 := md5.Sum(out)
-	md5str := hex.EncodeToString(md5[:])
 
-	if _, ok := history[md5str]; ok {
+	historyMu.Lock()
+	cfg := config
+	key, expires, ok := admit(source, state, mt, hex.EncodeToString(md5[:]), out, !isImg)
+	historyMu.Unlock()
+
+	if !ok {
 		return
 	}
 
-	if !isImg {
-		history[md5str] = Item{
-			Content: string(out),
-			Time:    time.Now(),
-		}
-	} else {
-		if file := saveImg(out, imgTypes[mt[0]]); file != "" {
-			history[md5str] = Item{
-				Img:      file,
-				Mimetype: mt[0],
-				Time:     time.Now(),
-			}
+	// reading all mimetypes and saving images starts processes and writes files, so it's done without blocking the
+	// history
+	types, offers := readOffers(b, mt, primary, img, out, cfg.MaxTypeSize)
+
+	item := Item{
+		Content:  string(out),
+		Mimetype: primary,
+		Time:     time.Now(),
+		Expires:  expires,
+		Types:    types,
+		Offers:   offers,
+		Sources:  []string{source},
+	}
+
+	if isImg {
+		item.Content = ""
+
+		if item.Img = saveImg(out, imgTypes[img]); item.Img == "" {
+			return
 		}
 	}
 
-	if len(history) > config.MaxItems {
-		trim()
-		saveToFile()
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	// the content might have been copied to the other selection meanwhile
+	if addSource(key, source) {
+		if item.Img != "" {
+			_ = os.Remove(item.Img)
+		}
 
 		return
 	}
 
+	history[key] = item
+
+	if !expires.IsZero() {
+		scheduleExpiry(expires)
+	}
+
+	trim()
 	saveToFile()
 }
 
-func trim() {
-	oldest := ""
-	oldestTime := time.Now()
+// admit checks if the content should be stored and returns its history key, along with the expiry of sensitive
+// content. Content already stored only gets the source added. Has to be called with historyMu held.
+func admit(source, state string, mt []string, hash string, out []byte, text bool) (string, time.Time, bool) {
+	key := hash
+	if source == SourcePrimary && !config.Primary.Merge {
+		key = fmt.Sprintf("%s:%s", SourcePrimary, hash)
+	}
 
-	for k, v := range history {
-		if v.Time.Before(oldestTime) {
-			oldest = k
-			oldestTime = v.Time
+	if config.MaxSize > 0 && len(out) > config.MaxSize {
+		slog.Info(Name, "ignored", "max_size", "size", len(out))
+		return "", time.Time{}, false
+	}
+
+	var expires time.Time
+
+	if isSensitive(state, mt, out, text) {
+		if config.SensitiveTTL <= 0 {
+			slog.Info(Name, "ignored", "sensitive")
+			return "", time.Time{}, false
 		}
+
+		expires = time.Now().Add(config.SensitiveTTL)
+	}
+
+	if addSource(key, source) {
+		return "", time.Time{}, false
+	}
+
+	return key, expires, true
+}
+
+// addSource adds the source to the stored item, returns false if nothing is stored for key.
+func addSource(key, source string) bool {
+	item, ok := history[key]
+	if !ok {
+		return false
 	}
 
-	if history[oldest].Img != "" {
-		_ = os.Remove(history[oldest].Img)
+	if !slices.Contains(item.sources(), source) {
+		item.Sources = append(item.sources(), source)
+		history[key] = item
+		saveToFile()
+	}
+
+	return true
+}
+
+// trim removes the oldest unpinned items until at most max_items unpinned items of each selection are left.
+func trim() {
+	trimSource(SourceClipboard, config.MaxItems)
+	trimSource(SourcePrimary, config.Primary.MaxItems)
+}
+
+func trimSource(source string, maxItems int) {
+	unpinned := 0
+
+	for _, v := range history {
+		if !v.Pinned && v.limitedBy() == source {
+			unpinned++
+		}
 	}
 
-	delete(history, oldest)
+	for ; unpinned > maxItems; unpinned-- {
+		oldest := ""
+		oldestTime := time.Now()
+
+		for k, v := range history {
+			if !v.Pinned && v.limitedBy() == source && v.Time.Before(oldestTime) {
+				oldest = k
+				oldestTime = v.Time
+			}
+		}
+
+		deleteItem(oldest)
+	}
 }
 
 func saveImg(b []byte, ext string) string {
@@ -233,61 +387,128 @@ func saveImg(b []byte, ext string) string {
 	return file
 }
 
-func PrintDoc() {
+func (plugin) PrintDoc() {
 	fmt.Printf("### %s\n", NamePretty)
 	fmt.Println("Provides access to your clipboard history.")
 	fmt.Println()
 	util.PrintConfig(Config{}, Name)
 }
 
-func Cleanup(qid uint32) {}
+func (plugin) Cleanup(qid uint32) {}
+
+// Shutdown stops watching the clipboard and writes the history.
+func (plugin) Shutdown() {
+	for _, v := range []ClipboardBackend{backend, primaryBackend} {
+		if v != nil {
+			v.Close()
+		}
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	saveToFile()
+}
 
 const (
-	ActionCopy   = "copy"
-	ActionRemove = "remove"
+	ActionCopy        = "copy"
+	ActionCopyPrimary = "copy_primary"
+	ActionRemove      = "remove"
+	ActionPin         = "pin"
+	ActionUnpin       = "unpin"
+)
+
+// Actions returns the actions supported by Activate.
+func (plugin) Actions() []string {
+	return []string{ActionCopy, ActionCopyPrimary, ActionRemove, ActionPin, ActionUnpin}
+}
+
+var (
+	itemActions = []*pb.QueryResponse_Item_Action{
+		{Name: ActionCopy, Label: "Copy", Icon: "edit-copy", Default: true},
+		{Name: ActionCopyPrimary, Label: "Copy to primary selection", Icon: "edit-copy"},
+		{Name: ActionRemove, Label: "Remove", Icon: "edit-delete"},
+		{Name: ActionPin, Label: "Pin", Icon: "view-pin"},
+	}
+	pinnedItemActions = []*pb.QueryResponse_Item_Action{
+		{Name: ActionCopy, Label: "Copy", Icon: "edit-copy", Default: true},
+		{Name: ActionCopyPrimary, Label: "Copy to primary selection", Icon: "edit-copy"},
+		{Name: ActionRemove, Label: "Remove", Icon: "edit-delete"},
+		{Name: ActionUnpin, Label: "Unpin", Icon: "window-unpin"},
+	}
 )
 
-func Activate(qid uint32, identifier, action string, arguments string) {
+func (plugin) Activate(qid uint32, identifier, action string, arguments string) error {
 	if action == "" {
 		action = ActionCopy
 	}
 
+	if action == ActionCopy || action == ActionCopyPrimary {
+		return copyItem(identifier, action)
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	item, ok := history[identifier]
+	if !ok {
+		return provider.ErrUnknownIdentifier
+	}
+
 	switch action {
 	case ActionRemove:
-		if history[identifier].Img != "" {
-			_ = os.Remove(history[identifier].Img)
-		}
+		deleteItem(identifier)
 
-		delete(history, identifier)
+		saveToFile()
+	case ActionPin, ActionUnpin:
+		item.Pinned = action == ActionPin
+		history[identifier] = item
 
+		// unpinned items count towards max_items and expire again
+		trim()
+		removeExpired()
 		saveToFile()
-	case ActionCopy:
-		cmd := exec.Command("wl-copy")
+	default:
+		return fmt.Errorf("%w: %s", provider.ErrActionNotSupported, action)
+	}
 
-		item := history[identifier]
-		if item.Img != "" {
-			f, _ := os.ReadFile(item.Img)
-			cmd.Stdin = bytes.NewReader(f)
-		} else {
-			cmd.Stdin = strings.NewReader(item.Content)
-		}
+	return nil
+}
 
-		err := cmd.Start()
-		if err != nil {
-			slog.Error("clipboard", "activate", err)
-		} else {
-			go func() {
-				cmd.Wait()
-			}()
-		}
-	default:
-		slog.Error(Name, "activate", fmt.Sprintf("no such action '%s'", action))
+// copyItem writes the item to the clipboard or the primary selection. The history isn't locked while writing, as
+// backends notify the watcher about the change.
+func copyItem(identifier, action string) error {
+	historyMu.Lock()
+	item, ok := history[identifier]
+	historyMu.Unlock()
+
+	if !ok {
+		return provider.ErrUnknownIdentifier
+	}
+
+	b := backend
+	if action == ActionCopyPrimary {
+		b = primaryBackend
 	}
+
+	offers, err := item.offers()
+	if err == nil {
+		err = b.Write(offers)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", provider.ErrCommandFailed, err)
+	}
+
+	return nil
 }
 
-func Query(qid uint32, iid uint32, text string, _ bool, exact bool) []*pb.QueryResponse_Item {
+func (plugin) Query(_ context.Context, qid uint32, iid uint32, text string, _ bool, exact bool) []*pb.QueryResponse_Item {
 	entries := []*pb.QueryResponse_Item{}
 
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
 	for k, v := range history {
 		e := &pb.QueryResponse_Item{
 			Identifier: k,
@@ -296,6 +517,17 @@ func Query(qid uint32, iid uint32, text string, _ bool, exact bool) []*pb.QueryR
 			Subtext:    v.Time.Format(time.RFC1123Z),
 			Type:       pb.QueryResponse_REGULAR,
 			Provider:   Name,
+			Actions:    itemActions,
+			Mimetype:   v.primary(),
+		}
+
+		if config.Primary.Enabled || slices.Contains(v.Sources, SourcePrimary) {
+			e.Subtext = fmt.Sprintf("%s, %s", strings.Join(v.sources(), "+"), e.Subtext)
+		}
+
+		if v.Pinned {
+			e.Subtext = fmt.Sprintf("pinned, %s", e.Subtext)
+			e.Actions = pinnedItemActions
 		}
 
 		if text != "" {
@@ -317,11 +549,19 @@ func Query(qid uint32, iid uint32, text string, _ bool, exact bool) []*pb.QueryR
 	}
 
 	if text == "" {
+		// pinned items first, newest first
 		slices.SortStableFunc(entries, func(a, b *pb.QueryResponse_Item) int {
-			ta, _ := time.Parse(time.RFC1123Z, a.Subtext)
-			tb, _ := time.Parse(time.RFC1123Z, b.Subtext)
+			ia, ib := history[a.Identifier], history[b.Identifier]
+
+			if ia.Pinned != ib.Pinned {
+				if ia.Pinned {
+					return -1
+				}
 
-			return ta.Compare(tb) * -1
+				return 1
+			}
+
+			return ia.Time.Compare(ib.Time) * -1
 		})
 
 		for k := range entries {
@@ -332,18 +572,6 @@ func Query(qid uint32, iid uint32, text string, _ bool, exact bool) []*pb.QueryR
 	return entries
 }
 
-func getMimetypes() []string {
-	cmd := exec.Command("wl-paste", "--list-types")
-
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		log.Println(string(out))
-		log.Panic(err)
-	}
-
-	return strings.Fields(string(out))
-}
-
-func Icon() string {
+func (plugin) Icon() string {
 	return config.Icon
 }
@@ -0,0 +1,134 @@
+package clipboard
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// waylandBackend uses wl-paste of wl-clipboard. Copied content is served with a data control protocol, wl-copy is
+// used if the compositor doesn't support any.
+type waylandBackend struct {
+	primary bool
+
+	mu      sync.Mutex
+	watcher *exec.Cmd
+	closed  bool
+}
+
+func (b *waylandBackend) Watch(fn func(state string)) error {
+	// wl-paste only passes the state of the clipboard to the command
+	cmd := exec.Command("wl-paste", b.args("--watch", "sh", "-c", `echo "$CLIPBOARD_STATE"`)...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+
+	err = cmd.Start()
+	b.watcher = cmd
+	b.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+
+	for scanner.Scan() {
+		fn(scanner.Text())
+	}
+
+	err = cmd.Wait()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+
+	return err
+}
+
+func (b *waylandBackend) Types() ([]string, error) {
+	out, err := wlPaste(b.args("--list-types")...)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(string(out)), nil
+}
+
+func (b *waylandBackend) Read(mimetype string) ([]byte, error) {
+	if mimetype == "" {
+		return wlPaste(b.args("-n")...)
+	}
+
+	return wlPaste(b.args("-n", "-t", mimetype)...)
+}
+
+// args prepends the selection flag, wl-clipboard uses the regular clipboard by default.
+func (b *waylandBackend) args(args ...string) []string {
+	if b.primary {
+		return append([]string{"--primary"}, args...)
+	}
+
+	return args
+}
+
+func wlPaste(args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("wl-paste", args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if strings.Contains(stderr.String(), "Nothing is copied") {
+			return nil, ErrEmpty
+		}
+
+		return nil, commandError(err, stderr.String())
+	}
+
+	return out, nil
+}
+
+func (b *waylandBackend) Write(offers []Offer) error {
+	if len(offers) == 0 {
+		return ErrEmpty
+	}
+
+	if err := b.offer(offers); !errors.Is(err, errNoDataControl) {
+		return err
+	}
+
+	// wl-copy detects text itself and offers all the aliases for it
+	args := b.args()
+	if !strings.HasPrefix(offers[0].Mimetype, "text/plain") {
+		args = append(args, "-t", offers[0].Mimetype)
+	}
+
+	return runInBackground(exec.Command("wl-copy", args...), offers[0].Content)
+}
+
+func (b *waylandBackend) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+
+	if b.watcher != nil && b.watcher.Process != nil {
+		b.watcher.Process.Kill()
+	}
+}
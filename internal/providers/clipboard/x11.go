@@ -0,0 +1,214 @@
+package clipboard
+
+import (
+	"bytes"
+	"crypto/md5"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// pollInterval is how often X11 clipboards are checked for changes if clipnotify isn't installed.
+const pollInterval = time.Second
+
+// x11Backend reads with xclip or xsel, xsel only supports text. Changes are detected with clipnotify if installed,
+// otherwise by polling. Copied content is served by owning the selection.
+type x11Backend struct {
+	tool      string
+	selection string
+
+	mu       sync.Mutex
+	notifier *exec.Cmd
+	done     chan struct{}
+	once     sync.Once
+}
+
+func (b *x11Backend) Watch(fn func(state string)) error {
+	if _, err := exec.LookPath("clipnotify"); err == nil {
+		return b.notify(fn)
+	}
+
+	return b.poll(fn)
+}
+
+// notify waits for changes with clipnotify, which exits on every change of a selection.
+func (b *x11Backend) notify(fn func(state string)) error {
+	fn("")
+
+	for {
+		cmd := exec.Command("clipnotify", "-s", b.selection)
+
+		b.mu.Lock()
+		select {
+		case <-b.done:
+			b.mu.Unlock()
+			return nil
+		default:
+		}
+
+		err := cmd.Start()
+		b.notifier = cmd
+		b.mu.Unlock()
+
+		if err != nil {
+			return err
+		}
+
+		err = cmd.Wait()
+
+		select {
+		case <-b.done:
+			return nil
+		default:
+		}
+
+		if err != nil {
+			return err
+		}
+
+		fn("")
+	}
+}
+
+// poll compares the offered types and text with the previous ones.
+func (b *x11Backend) poll(fn func(state string)) error {
+	var last [md5.Size]byte
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		types, _ := b.Types()
+		text, _ := b.Read("")
+
+		sum := md5.Sum(append([]byte(strings.Join(types, "\n")), text...))
+		if sum != last {
+			last = sum
+			fn("")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-b.done:
+			return nil
+		}
+	}
+}
+
+// x11Targets are targets not describing the content.
+var x11Targets = []string{"TARGETS", "TIMESTAMP", "MULTIPLE", "SAVE_TARGETS", "UTF8_STRING", "STRING", "TEXT", "COMPOUND_TEXT"}
+
+func (b *x11Backend) Types() ([]string, error) {
+	if b.tool == "xsel" {
+		out, err := b.run("xsel", "--"+b.selection, "--output")
+		if err != nil {
+			return nil, err
+		}
+
+		if err := nonEmpty(out); err != nil {
+			return nil, err
+		}
+
+		return []string{"text/plain"}, nil
+	}
+
+	out, err := b.run("xclip", "-selection", b.selection, "-o", "-t", "TARGETS")
+	if err != nil {
+		return nil, err
+	}
+
+	res := []string{}
+	text := false
+
+	for _, v := range strings.Fields(string(out)) {
+		if slices.Contains(x11Targets, v) {
+			text = text || v == "UTF8_STRING" || v == "STRING" || v == "TEXT"
+			continue
+		}
+
+		res = append(res, v)
+	}
+
+	if text && !slices.ContainsFunc(res, func(v string) bool { return strings.HasPrefix(v, "text/plain") }) {
+		res = append(res, "text/plain")
+	}
+
+	if len(res) == 0 {
+		return nil, ErrEmpty
+	}
+
+	return res, nil
+}
+
+func (b *x11Backend) Read(mimetype string) ([]byte, error) {
+	if b.tool == "xsel" {
+		out, err := b.run("xsel", "--"+b.selection, "--output")
+		if err != nil {
+			return nil, err
+		}
+
+		return out, nonEmpty(out)
+	}
+
+	args := []string{"-selection", b.selection, "-o"}
+	if mimetype != "" && !strings.HasPrefix(mimetype, "text/plain") {
+		args = append(args, "-t", mimetype)
+	}
+
+	out, err := b.run("xclip", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nonEmpty(out)
+}
+
+func (b *x11Backend) run(name string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		// xclip fails if the target isn't available, f.e. if nothing is copied
+		if strings.Contains(stderr.String(), "not available") {
+			return nil, ErrEmpty
+		}
+
+		return nil, commandError(err, stderr.String())
+	}
+
+	return out, nil
+}
+
+func nonEmpty(out []byte) error {
+	if len(out) == 0 {
+		return ErrEmpty
+	}
+
+	return nil
+}
+
+func (b *x11Backend) Write(offers []Offer) error {
+	if len(offers) == 0 {
+		return ErrEmpty
+	}
+
+	return b.own(offers)
+}
+
+func (b *x11Backend) Close() {
+	b.once.Do(func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		close(b.done)
+
+		if b.notifier != nil && b.notifier.Process != nil {
+			b.notifier.Process.Kill()
+		}
+	})
+}
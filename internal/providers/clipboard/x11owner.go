@@ -0,0 +1,263 @@
+package clipboard
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+// x11Owner owns a selection and serves all offers until another client takes it.
+type x11Owner struct {
+	conn   *xgb.Conn
+	window xproto.Window
+	time   xproto.Timestamp
+	// chunk is the maximum content sent at once, bigger content is transferred incrementally
+	chunk int
+
+	atoms     map[string]xproto.Atom
+	targets   []xproto.Atom
+	content   map[xproto.Atom][]byte
+	transfers map[x11Transfer][]byte
+	cleared   bool
+}
+
+// x11Transfer is an incremental transfer to a property of a requestor.
+type x11Transfer struct {
+	requestor xproto.Window
+	property  xproto.Atom
+	target    xproto.Atom
+}
+
+// own takes the selection with all offers, which are served until another client takes the selection.
+func (b *x11Backend) own(offers []Offer) error {
+	conn, err := xgb.NewConn()
+	if err != nil {
+		return err
+	}
+
+	o := &x11Owner{
+		conn:      conn,
+		atoms:     make(map[string]xproto.Atom),
+		content:   make(map[xproto.Atom][]byte),
+		transfers: make(map[x11Transfer][]byte),
+	}
+
+	if err := o.setSelection(strings.ToUpper(b.selection), offers); err != nil {
+		conn.Close()
+		return err
+	}
+
+	go o.serve()
+
+	return nil
+}
+
+func (o *x11Owner) setSelection(selection string, offers []Offer) error {
+	setup := xproto.Setup(o.conn)
+	screen := setup.DefaultScreen(o.conn)
+
+	// the maximum request length is in units of 4 bytes, minus the header of ChangeProperty
+	o.chunk = int(setup.MaximumRequestLength)*4 - 24
+
+	window, err := xproto.NewWindowId(o.conn)
+	if err != nil {
+		return err
+	}
+
+	o.window = window
+
+	err = xproto.CreateWindowChecked(o.conn, 0, window, screen.Root, 0, 0, 1, 1, 0, xproto.WindowClassInputOnly, 0,
+		xproto.CwEventMask, []uint32{xproto.EventMaskPropertyChange}).Check()
+	if err != nil {
+		return err
+	}
+
+	names, content := targets(offers)
+
+	for _, v := range append([]string{selection, "TARGETS", "TIMESTAMP", "INCR", "UTF8_STRING", "TEXT"}, names...) {
+		reply, err := xproto.InternAtom(o.conn, false, uint16(len(v)), v).Reply()
+		if err != nil {
+			return err
+		}
+
+		o.atoms[v] = reply.Atom
+	}
+
+	for _, v := range names {
+		o.targets = append(o.targets, o.atoms[v])
+		o.content[o.atoms[v]] = content[v]
+	}
+
+	if o.time, err = o.timestamp(); err != nil {
+		return err
+	}
+
+	if err := xproto.SetSelectionOwnerChecked(o.conn, window, o.atoms[selection], o.time).Check(); err != nil {
+		return err
+	}
+
+	owner, err := xproto.GetSelectionOwner(o.conn, o.atoms[selection]).Reply()
+	if err != nil {
+		return err
+	}
+
+	if owner.Owner != window {
+		return errors.New("couldn't take the selection")
+	}
+
+	return nil
+}
+
+// timestamp returns the current server time, selections shouldn't be owned with CurrentTime. Appending nothing to a
+// property is the usual way to get it.
+func (o *x11Owner) timestamp() (xproto.Timestamp, error) {
+	err := xproto.ChangePropertyChecked(o.conn, xproto.PropModeAppend, o.window, o.atoms["TIMESTAMP"],
+		xproto.AtomInteger, 32, 0, nil).Check()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		ev, err := o.conn.WaitForEvent()
+		if err != nil {
+			return 0, err
+		}
+
+		if ev == nil {
+			return 0, errors.New("connection closed")
+		}
+
+		if ev, ok := ev.(xproto.PropertyNotifyEvent); ok && ev.Window == o.window {
+			return ev.Time, nil
+		}
+	}
+}
+
+// serve answers requests for the selection until another client takes it and all transfers are done.
+func (o *x11Owner) serve() {
+	defer o.conn.Close()
+
+	for !o.cleared || len(o.transfers) > 0 {
+		// errors of requests to windows of other clients are ignored, f.e. if the requestor got closed
+		ev, err := o.conn.WaitForEvent()
+		if ev == nil && err == nil {
+			return
+		}
+
+		switch ev := ev.(type) {
+		case xproto.SelectionClearEvent:
+			o.cleared = true
+		case xproto.SelectionRequestEvent:
+			o.request(ev)
+		case xproto.PropertyNotifyEvent:
+			if ev.State == xproto.PropertyDelete {
+				o.transfer(ev.Window, ev.Atom)
+			}
+		}
+	}
+}
+
+// request stores the requested target in the property of the requestor and notifies it.
+func (o *x11Owner) request(ev xproto.SelectionRequestEvent) {
+	property := ev.Property
+
+	// obsolete clients don't pass a property
+	if property == xproto.AtomNone {
+		property = ev.Target
+	}
+
+	if o.cleared || !o.reply(ev.Requestor, ev.Target, property) {
+		property = xproto.AtomNone
+	}
+
+	notify := xproto.SelectionNotifyEvent{
+		Time:      ev.Time,
+		Requestor: ev.Requestor,
+		Selection: ev.Selection,
+		Target:    ev.Target,
+		Property:  property,
+	}
+
+	xproto.SendEvent(o.conn, false, ev.Requestor, xproto.EventMaskNoEvent, string(notify.Bytes()))
+}
+
+// reply stores the target in the property, returns false if the target isn't offered.
+func (o *x11Owner) reply(requestor xproto.Window, target, property xproto.Atom) bool {
+	switch target {
+	case o.atoms["TARGETS"]:
+		atoms := append([]xproto.Atom{o.atoms["TARGETS"], o.atoms["TIMESTAMP"]}, o.targets...)
+
+		data := make([]byte, 4*len(atoms))
+		for k, v := range atoms {
+			xgb.Put32(data[4*k:], uint32(v))
+		}
+
+		xproto.ChangeProperty(o.conn, xproto.PropModeReplace, requestor, property, xproto.AtomAtom, 32,
+			uint32(len(atoms)), data)
+	case o.atoms["TIMESTAMP"]:
+		data := make([]byte, 4)
+		xgb.Put32(data, uint32(o.time))
+
+		xproto.ChangeProperty(o.conn, xproto.PropModeReplace, requestor, property, xproto.AtomInteger, 32, 1, data)
+	default:
+		if !slices.Contains(o.targets, target) {
+			return false
+		}
+
+		content := o.content[target]
+
+		if len(content) > o.chunk {
+			// the requestor deletes the property to ask for the next chunk
+			xproto.ChangeWindowAttributes(o.conn, requestor, xproto.CwEventMask,
+				[]uint32{xproto.EventMaskPropertyChange})
+
+			o.transfers[x11Transfer{requestor: requestor, property: property, target: target}] = content
+
+			size := make([]byte, 4)
+			xgb.Put32(size, uint32(len(content)))
+
+			xproto.ChangeProperty(o.conn, xproto.PropModeReplace, requestor, property, o.atoms["INCR"], 32, 1, size)
+
+			return true
+		}
+
+		xproto.ChangeProperty(o.conn, xproto.PropModeReplace, requestor, property, o.propertyType(target), 8,
+			uint32(len(content)), content)
+	}
+
+	return true
+}
+
+// transfer sends the next chunk of an incremental transfer, an empty chunk finishes it.
+func (o *x11Owner) transfer(requestor xproto.Window, property xproto.Atom) {
+	for k, content := range o.transfers {
+		if k.requestor != requestor || k.property != property {
+			continue
+		}
+
+		n := min(len(content), o.chunk)
+
+		xproto.ChangeProperty(o.conn, xproto.PropModeReplace, requestor, property, o.propertyType(k.target), 8,
+			uint32(n), content[:n])
+
+		if n == 0 {
+			delete(o.transfers, k)
+		} else {
+			o.transfers[k] = content[n:]
+		}
+
+		return
+	}
+}
+
+// propertyType is the type content of a target is stored as, TEXT is just a request for any text encoding.
+func (o *x11Owner) propertyType(target xproto.Atom) xproto.Atom {
+	if target == o.atoms["TEXT"] {
+		return o.atoms["UTF8_STRING"]
+	}
+
+	return target
+}
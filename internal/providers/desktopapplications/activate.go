@@ -1,4 +1,4 @@
-package main
+package desktopapplications
 
 import (
 	"fmt"
@@ -8,11 +8,26 @@ import (
 	"syscall"
 
 	"github.com/abenz1267/elephant/internal/common"
+	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"github.com/abenz1267/elephant/pkg/provider"
 )
 
-func Activate(qid uint32, identifier, action string, arguments string) {
+const ActionStart = "start"
+
+// Actions returns the actions supported by Activate.
+func (plugin) Actions() []string {
+	return []string{ActionStart}
+}
+
+var itemActions = []*pb.QueryResponse_Item_Action{
+	{Name: ActionStart, Label: "Start", Icon: "system-run", Default: true},
+}
+
+func (plugin) Activate(qid uint32, identifier, action string, arguments string) error {
+	cfg := config.Load()
+
 	toRun := ""
-	prefix := common.LaunchPrefix(config.LaunchPrefix)
+	prefix := common.LaunchPrefix(cfg.LaunchPrefix)
 
 	splits := strings.Split(arguments, common.GetElephantConfig().ArgumentDelimiter)
 	if len(splits) > 1 {
@@ -23,15 +38,31 @@ func Activate(qid uint32, identifier, action string, arguments string) {
 
 	parts := strings.Split(identifier, ":")
 
+	filesMu.RLock()
+	file, ok := files[parts[0]]
+	filesMu.RUnlock()
+
+	if !ok {
+		return provider.ErrUnknownIdentifier
+	}
+
+	if action != "" && action != ActionStart {
+		return fmt.Errorf("%w: %s", provider.ErrActionNotSupported, action)
+	}
+
 	if len(parts) == 2 {
-		for _, v := range files[parts[0]].Actions {
+		for _, v := range file.Actions {
 			if v.Action == parts[1] {
 				toRun = v.Exec
 				break
 			}
 		}
+
+		if toRun == "" {
+			return provider.ErrUnknownIdentifier
+		}
 	} else {
-		toRun = files[parts[0]].Exec
+		toRun = file.Exec
 	}
 
 	cmd := exec.Command("sh", "-c", strings.TrimSpace(fmt.Sprintf("%s %s %s", prefix, toRun, arguments)))
@@ -41,14 +72,19 @@ func Activate(qid uint32, identifier, action string, arguments string) {
 
 	err := cmd.Start()
 	if err != nil {
-		slog.Error(Name, "activate", identifier, "error", err)
+		return fmt.Errorf("%w: %w", provider.ErrCommandFailed, err)
 	}
 
 	go func() {
 		cmd.Wait()
 	}()
 
-	if config.History {
+	if cfg.History {
+		key := file.ID
+		if len(parts) == 2 {
+			key = historyKey(file.ID, parts[1])
+		}
+
 		var last uint32
 
 		for k := range results.Queries[qid] {
@@ -58,11 +94,13 @@ func Activate(qid uint32, identifier, action string, arguments string) {
 		}
 
 		if last != 0 {
-			h.Save(results.Queries[qid][last], identifier)
+			h.Save(results.Queries[qid][last], key)
 		} else {
-			h.Save("", identifier)
+			h.Save("", key)
 		}
 	}
 
 	slog.Info(Name, "activated", identifier)
+
+	return nil
 }
@@ -1,8 +1,8 @@
-package main
+package desktopapplications
 
 import "log/slog"
 
-func Cleanup(qid uint32) {
+func (plugin) Cleanup(qid uint32) {
 	slog.Info(Name, "cleanup", qid)
 
 	results.Lock()
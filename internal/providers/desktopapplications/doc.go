@@ -1,4 +1,4 @@
-package main
+package desktopapplications
 
 import (
 	"fmt"
@@ -6,7 +6,7 @@ import (
 	"github.com/abenz1267/elephant/internal/util"
 )
 
-func PrintDoc() {
+func (plugin) PrintDoc() {
 	fmt.Printf("### %s\n", NamePretty)
 	fmt.Println("Provides access to all your installed desktop applications.")
 	fmt.Println()
@@ -1,15 +1,18 @@
-package main
+package desktopapplications
 
 import (
+	"fmt"
 	"io/fs"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/abenz1267/elephant/internal/comm/handlers"
 	"github.com/adrg/xdg"
 	"github.com/charlievieth/fastwalk"
 	"github.com/fsnotify/fsnotify"
@@ -23,12 +26,18 @@ var (
 	filesMu       sync.RWMutex
 	watcherDirsMu sync.RWMutex
 	watcher       *fsnotify.Watcher
-	regionLocale  = ""
-	langLocale    = ""
-	dirs          []string
+	// locale is replaced when the config changes, while files are parsed by the watcher
+	locale atomic.Pointer[desktopLocale]
+	dirs   []string
 )
 
-func loadFiles() {
+// desktopLocale holds the locales used for localized keys, f.e. "de_DE" and "de".
+type desktopLocale struct {
+	region string
+	lang   string
+}
+
+func loadFiles() error {
 	start := time.Now()
 	setVars()
 	conf := fastwalk.Config{
@@ -38,8 +47,7 @@ func loadFiles() {
 	var err error
 	watcher, err = fsnotify.NewWatcher()
 	if err != nil {
-		slog.Error(Name, "watcher_init", err)
-		return
+		return err
 	}
 
 	for _, root := range dirs {
@@ -48,8 +56,7 @@ func loadFiles() {
 		}
 
 		if err := fastwalk.Walk(&conf, root, walkFunction); err != nil {
-			slog.Error(Name, "walk", err)
-			os.Exit(1)
+			return err
 		}
 	}
 
@@ -59,6 +66,8 @@ func loadFiles() {
 	slog.Info(Name, "watcher_dirs", len(watchedDirs))
 	go watchFiles()
 	slog.Info(Name, "watcher", "started")
+
+	return nil
 }
 
 func setVars() {
@@ -73,9 +82,10 @@ func setVars() {
 }
 
 func walkFunction(path string, d fs.DirEntry, err error) error {
+	// unreadable directories are skipped instead of aborting the walk
 	if err != nil {
 		slog.Error(Name, "walk", err)
-		os.Exit(1)
+		return nil
 	}
 
 	filesMu.RLock()
@@ -94,7 +104,7 @@ func walkFunction(path string, d fs.DirEntry, err error) error {
 		addDirToWatcher(path, watchedDirs)
 	}
 
-	return err
+	return nil
 }
 
 func trackSymlinks(filename string) {
@@ -194,7 +204,7 @@ func handleFileEvent(event fsnotify.Event) {
 		handleFileRemove(event.Name)
 	}
 
-	handlers.ProviderUpdated <- Name
+	updated(Name)
 }
 
 func handleFileCreate(path string) {
@@ -273,13 +283,32 @@ func addNewEntry(path string) {
 		}
 	}
 
+	l := locale.Load()
+
+	f, err := parseFile(path, l.lang, l.region)
+	if err != nil {
+		slog.Error(Name, "parse", err)
+		return
+	}
+
 	filesMu.Lock()
-	files[path] = parseFile(path, langLocale, regionLocale)
+	files[path] = f
 	filesMu.Unlock()
 }
 
+// reparseFiles parses all known desktop files again, f.e. after the locale changed.
+func reparseFiles() {
+	filesMu.RLock()
+	paths := slices.Collect(maps.Keys(files))
+	filesMu.RUnlock()
+
+	for _, v := range paths {
+		addNewEntry(v)
+	}
+}
+
 func getLocale() {
-	regionLocale = config.Locale
+	regionLocale := config.Load().Locale
 
 	if regionLocale == "" {
 		regionLocale = os.Getenv("LANG")
@@ -297,7 +326,7 @@ func getLocale() {
 		regionLocale = strings.Split(regionLocale, ".")[0]
 	}
 
-	langLocale = strings.Split(regionLocale, "_")[0]
+	locale.Store(&desktopLocale{region: regionLocale, lang: strings.Split(regionLocale, "_")[0]})
 }
 
 func isSymlink(filename string) (string, bool) {
@@ -316,6 +345,28 @@ func isSymlink(filename string) (string, bool) {
 	return targetPath, true
 }
 
+// desktopFileID returns the desktop file ID as defined by the XDG desktop entry spec: the path relative to the
+// applications dir, with slashes replaced by dashes. Files outside of the applications dirs use their filename.
+func desktopFileID(path string) string {
+	for _, v := range xdg.ApplicationDirs {
+		rel, err := filepath.Rel(v, path)
+		if err == nil && !strings.HasPrefix(rel, "..") {
+			return strings.ReplaceAll(rel, string(filepath.Separator), "-")
+		}
+	}
+
+	return filepath.Base(path)
+}
+
+// historyKey returns the key of a desktop file or one of its actions in the history.
+func historyKey(id, action string) string {
+	if action == "" {
+		return id
+	}
+
+	return fmt.Sprintf("%s:%s", id, action)
+}
+
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
@@ -1,4 +1,4 @@
-package main
+package desktopapplications
 
 import (
 	"bytes"
@@ -30,16 +30,17 @@ type Data struct {
 	Keywords       []string
 }
 
-func parseFile(path, l, ll string) *DesktopFile {
+func parseFile(path, l, ll string) (*DesktopFile, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		slog.Error(Name, "parseFile", err)
-		os.Exit(1)
+		return nil, err
 	}
 
 	parts := splitIntoParsebles(data)
 
-	f := &DesktopFile{}
+	f := &DesktopFile{
+		ID: desktopFileID(path),
+	}
 
 	for i, v := range parts {
 		data := parseData(v, l, ll)
@@ -48,7 +49,7 @@ func parseFile(path, l, ll string) *DesktopFile {
 			f.Data = data
 
 			if f.Icon == "" {
-				f.Icon = config.IconPlaceholder
+				f.Icon = config.Load().IconPlaceholder
 			}
 		} else {
 			f.Actions = append(f.Actions, data)
@@ -86,7 +87,7 @@ func parseFile(path, l, ll string) *DesktopFile {
 		}
 	}
 
-	return f
+	return f, nil
 }
 
 func parseData(in []byte, l, ll string) Data {
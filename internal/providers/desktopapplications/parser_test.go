@@ -0,0 +1,91 @@
+package desktopapplications
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const testDesktopFile = `[Desktop Entry]
+Name=Firefox
+Name[de]=Feuerfuchs
+Comment=Browse the web
+Exec=firefox %u
+Icon=firefox
+Categories=Network;WebBrowser;
+Keywords=web;browser;
+
+[Desktop Action new-private-window]
+Name=New Private Window
+Exec=firefox --private-window %u
+`
+
+func TestParseFile(t *testing.T) {
+	config.Store(defaultConfig())
+
+	path := filepath.Join(t.TempDir(), "firefox.desktop")
+
+	if err := os.WriteFile(path, []byte(testDesktopFile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parseFile(path, "de_DE", "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Name != "Feuerfuchs" || f.Exec != "firefox" || f.Icon != "firefox" {
+		t.Errorf("got name %q, exec %q, icon %q", f.Name, f.Exec, f.Icon)
+	}
+
+	if len(f.Actions) != 1 {
+		t.Fatalf("got %d actions, want 1", len(f.Actions))
+	}
+
+	a := f.Actions[0]
+
+	if a.Action != "new-private-window" || a.Exec != "firefox --private-window" || a.Parent != "Feuerfuchs" {
+		t.Errorf("got action %q, exec %q, parent %q", a.Action, a.Exec, a.Parent)
+	}
+
+	// actions inherit from the entry
+	if a.Icon != "firefox" || !slices.Equal(a.Categories, f.Categories) {
+		t.Errorf("got icon %q, categories %v", a.Icon, a.Categories)
+	}
+}
+
+func TestParseFileMissing(t *testing.T) {
+	config.Store(defaultConfig())
+
+	if _, err := parseFile(filepath.Join(t.TempDir(), "missing.desktop"), "", ""); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestParseExec(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"firefox %u", "firefox"},
+		{`sh -c "echo hello"`, `sh -c "echo hello"`},
+		{"env FOO=bar app %F --flag", "env FOO=bar app --flag"},
+	}
+
+	for _, tt := range tests {
+		got, err := parseExec(tt.in)
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := parseExec(""); err == nil {
+		t.Error("empty exec line: expected an error")
+	}
+}
@@ -1,6 +1,7 @@
-package main
+package desktopapplications
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
@@ -15,7 +16,9 @@ import (
 
 var results = providers.QueryData{}
 
-func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.QueryResponse_Item {
+func (plugin) Query(_ context.Context, qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.QueryResponse_Item {
+	cfg := config.Load()
+
 	start := time.Now()
 	desktop := os.Getenv("XDG_CURRENT_DESKTOP")
 	entries := make([]*pb.QueryResponse_Item, 0, len(files)*2) // Estimate for entries + action
@@ -27,7 +30,7 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 	}
 
 	alias := ""
-	if val, ok := config.Aliases[query]; ok {
+	if val, ok := cfg.Aliases[query]; ok {
 		alias = val
 	}
 
@@ -45,6 +48,7 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 				Subtext:    v.GenericName,
 				Icon:       v.Icon,
 				Provider:   Name,
+				Actions:    itemActions,
 				Score:      1_000_000,
 			})
 			continue
@@ -68,13 +72,13 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 		}
 
 		var usageScore int32
-		if config.History && (score > config.MinScore || query == "") {
-			usageScore = h.CalcUsageScore(query, k)
+		if cfg.History && (score > cfg.MinScore || query == "") {
+			usageScore = h.CalcUsageScore(query, v.ID)
 			score = score + usageScore
 		}
 
-		if usageScore != 0 || config.ShowActions && config.ShowGeneric || !config.ShowActions || (config.ShowActions && len(v.Actions) == 0) || query == "" {
-			if score >= config.MinScore || query == "" {
+		if usageScore != 0 || cfg.ShowActions && cfg.ShowGeneric || !cfg.ShowActions || (cfg.ShowActions && len(v.Actions) == 0) || query == "" {
+			if score >= cfg.MinScore || query == "" {
 				entries = append(entries, &pb.QueryResponse_Item{
 					Identifier: k,
 					Text:       v.Name,
@@ -82,6 +86,7 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 					Subtext:    subtext,
 					Icon:       v.Icon,
 					Provider:   Name,
+					Actions:    itemActions,
 					Score:      score,
 					Fuzzyinfo: &pb.QueryResponse_Item_FuzzyInfo{
 						Start:     fs,
@@ -105,6 +110,7 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 					Subtext:    v.Name,
 					Icon:       a.Icon,
 					Provider:   Name,
+					Actions:    itemActions,
 				})
 				continue
 			}
@@ -127,13 +133,13 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 			}
 
 			var usageScore int32
-			if config.History && (score > config.MinScore || query == "") {
-				usageScore = h.CalcUsageScore(query, identifier)
+			if cfg.History && (score > cfg.MinScore || query == "") {
+				usageScore = h.CalcUsageScore(query, historyKey(v.ID, a.Action))
 				score = score + usageScore
 			}
 
-			if (query == "" && config.ShowActionsWithoutQuery) || (query != "" && config.ShowActions) || usageScore != 0 {
-				if score >= config.MinScore || query == "" {
+			if (query == "" && cfg.ShowActionsWithoutQuery) || (query != "" && cfg.ShowActions) || usageScore != 0 {
+				if score >= cfg.MinScore || query == "" {
 					entries = append(entries, &pb.QueryResponse_Item{
 						Identifier: identifier,
 						Score:      score,
@@ -142,6 +148,7 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 						Subtext:    subtext,
 						Icon:       a.Icon,
 						Provider:   Name,
+						Actions:    itemActions,
 						Fuzzyinfo: &pb.QueryResponse_Item_FuzzyInfo{
 							Start:     fs,
 							Field:     field,
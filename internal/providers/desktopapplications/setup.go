@@ -1,25 +1,50 @@
-package main
+// Package desktopapplications provides access to installed desktop applications.
+package desktopapplications
 
 import (
 	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/abenz1267/elephant/internal/common"
 	"github.com/abenz1267/elephant/internal/common/history"
+	"github.com/abenz1267/elephant/pkg/provider"
 )
 
 type DesktopFile struct {
 	Data
 	Actions []Data
+	// ID is the desktop file ID, used as stable key for the history
+	ID string
 }
 
 var (
 	Name       = "desktopapplications"
 	NamePretty = "Desktop Applications"
-	h          = history.Load(Name)
-	config     *Config
+	h          *history.History
+	config     atomic.Pointer[Config]
+	updated    = func(value string) {}
 )
 
+type plugin struct{}
+
+// Provider is looked up by elephant when loading the plugin.
+var Provider provider.Provider = plugin{}
+
+func (plugin) APIVersion() int {
+	return provider.APIVersion
+}
+
+func (plugin) Name() string {
+	return Name
+}
+
+func (plugin) NamePretty() string {
+	return NamePretty
+}
+
 type Config struct {
 	common.Config           `koanf:",squash"`
 	LaunchPrefix            string            `koanf:"launch_prefix" desc:"overrides the default app2unit or uwsm prefix, if set. 'CLEAR' to not prefix." default:""`
@@ -28,13 +53,52 @@ type Config struct {
 	ShowGeneric             bool              `koanf:"show_generic" desc:"include generic info when show_actions is true" default:"false"`
 	ShowActionsWithoutQuery bool              `koanf:"show_actions_without_query" desc:"show application actions, if the search query is empty" default:"false"`
 	History                 bool              `koanf:"history" desc:"make use of history for sorting" default:"false"`
+	Scoring                 history.Scoring   `koanf:"scoring" desc:"how history is used for sorting" default:""`
 	IconPlaceholder         string            `koanf:"icon_placeholder" desc:"placeholder icon for apps without icon" default:"applications-other"`
 	Aliases                 map[string]string `koanf:"aliases" desc:"setup aliases for applications. Matched aliases will always be placed on top of the list. Example: 'ffp' => '<identifier>'. Check elephant log output when activating an item to get its identifier." default:""`
 }
 
-func init() {
+func (plugin) Setup() error {
 	start := time.Now()
-	config = &Config{
+	h = history.Load(Name)
+
+	c := defaultConfig()
+
+	if err := common.ReadConfig(Name, c); err != nil {
+		return err
+	}
+
+	config.Store(c)
+
+	if err := h.SetScoring(c.Scoring); err != nil {
+		slog.Error(Name, "scoring", err)
+	}
+
+	if err := loadFiles(); err != nil {
+		return err
+	}
+
+	// history used to be keyed by the path, it's the desktop file ID now, so it survives moved files
+	h.Migrate(func(identifier string) (string, bool) {
+		path, action, _ := strings.Cut(identifier, ":")
+		if !filepath.IsAbs(path) {
+			return "", false
+		}
+
+		return historyKey(desktopFileID(path), action), true
+	})
+
+	slog.Info(Name, "desktop files", len(files), "time", time.Since(start))
+
+	return nil
+}
+
+func (plugin) DefaultConfig() any {
+	return defaultConfig()
+}
+
+func defaultConfig() *Config {
+	return &Config{
 		Config: common.Config{
 			Icon:     "applications-other",
 			MinScore: 30,
@@ -43,17 +107,38 @@ func init() {
 		ShowGeneric:             false,
 		ShowActionsWithoutQuery: false,
 		History:                 false,
+		Scoring:                 history.DefaultScoring(),
 		IconPlaceholder:         "applications-other",
 		Aliases:                 map[string]string{},
 	}
+}
 
-	common.LoadConfig(Name, config)
+func (plugin) Reload() error {
+	c := defaultConfig()
 
-	loadFiles()
+	if err := common.ReadConfig(Name, c); err != nil {
+		return err
+	}
 
-	slog.Info(Name, "desktop files", len(files), "time", time.Since(start))
+	if err := h.SetScoring(c.Scoring); err != nil {
+		return err
+	}
+
+	prev := config.Load().Locale
+	config.Store(c)
+
+	if c.Locale != prev {
+		getLocale()
+		reparseFiles()
+	}
+
+	return nil
+}
+
+func (plugin) Subscribe(fn func(value string)) {
+	updated = fn
 }
 
-func Icon() string {
-	return config.Icon
+func (plugin) Icon() string {
+	return config.Load().Icon
 }
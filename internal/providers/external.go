@@ -0,0 +1,456 @@
+package providers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"github.com/abenz1267/elephant/pkg/provider"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// JSON-RPC error codes external providers use to report provider errors.
+const (
+	externalErrUnknownIdentifier  = -32001
+	externalErrActionNotSupported = -32002
+	externalErrCommandFailed      = -32003
+)
+
+const (
+	externalTimeout = 5 * time.Second
+	// externalMaxLine is the maximum size of a single message sent by an external provider.
+	externalMaxLine = 16 * 1024 * 1024
+)
+
+var errExternalExited = errors.New("external provider exited")
+
+type rpcRequest struct {
+	JSONRPC string  `json:"jsonrpc"`
+	ID      *uint64 `json:"id,omitempty"`
+	Method  string  `json:"method"`
+	Params  any     `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Message)
+}
+
+type rpcMessage struct {
+	ID     *uint64         `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type externalInfo struct {
+	APIVersion int      `json:"api_version"`
+	Name       string   `json:"name"`
+	NamePretty string   `json:"name_pretty"`
+	Icon       string   `json:"icon"`
+	Actions    []string `json:"actions"`
+}
+
+// external is a provider running as its own process, speaking line-delimited JSON-RPC 2.0 over stdio.
+type external struct {
+	path string
+	info externalInfo
+	cmd  *exec.Cmd
+
+	mu      sync.Mutex
+	stdin   io.WriteCloser
+	nextID  uint64
+	pending map[uint64]chan rpcMessage
+	exited  chan struct{}
+	// logged is closed once stderr is drained, it has to be before waiting for the process
+	logged chan struct{}
+
+	updated func(value string)
+}
+
+// isExternal checks if the file is a candidate for an external provider: an executable file
+// located directly in one of the provider directories.
+func isExternal(root, path string) bool {
+	if filepath.Dir(path) != root || strings.HasPrefix(filepath.Base(path), ".") {
+		return false
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	return info.Mode().IsRegular() && info.Mode().Perm()&0o111 != 0
+}
+
+// openExternal starts the executable and performs the initialize handshake.
+func openExternal(path string) (provider.Provider, error) {
+	e := &external{
+		path:    path,
+		pending: make(map[uint64]chan rpcMessage),
+		exited:  make(chan struct{}),
+		logged:  make(chan struct{}),
+	}
+
+	e.cmd = exec.Command(path)
+	e.cmd.Dir = filepath.Dir(path)
+
+	var err error
+
+	e.stdin, err = e.cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := e.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stderr, err := e.cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	go e.read(stdout)
+	go e.log(stderr)
+
+	if err := e.call(context.Background(), "initialize", map[string]any{"api_version": provider.APIVersion}, &e.info); err != nil {
+		e.kill()
+		return nil, fmt.Errorf("initialize: %w", err)
+	}
+
+	switch {
+	case e.info.APIVersion != provider.APIVersion:
+		e.kill()
+		return nil, fmt.Errorf("provider API version %d, expected %d", e.info.APIVersion, provider.APIVersion)
+	case e.info.Name == "":
+		e.kill()
+		return nil, errors.New("initialize: missing name")
+	}
+
+	if e.info.NamePretty == "" {
+		e.info.NamePretty = e.info.Name
+	}
+
+	return e, nil
+}
+
+func (e *external) read(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), externalMaxLine)
+
+	for scanner.Scan() {
+		var msg rpcMessage
+
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			slog.Error("providers", "external", e.path, "invalid message", err)
+			continue
+		}
+
+		if msg.ID == nil {
+			e.notification(msg)
+			continue
+		}
+
+		e.mu.Lock()
+		ch, ok := e.pending[*msg.ID]
+		delete(e.pending, *msg.ID)
+		e.mu.Unlock()
+
+		if ok {
+			ch <- msg
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		slog.Error("providers", "external", e.path, "read", err)
+	}
+
+	e.mu.Lock()
+	close(e.exited)
+	e.pending = make(map[uint64]chan rpcMessage)
+	e.mu.Unlock()
+
+	<-e.logged
+
+	if err := e.cmd.Wait(); err != nil {
+		slog.Error("providers", "external", e.path, "exited", err)
+		return
+	}
+
+	slog.Info("providers", "external", e.path, "exited", "ok")
+}
+
+func (e *external) log(stderr io.Reader) {
+	defer close(e.logged)
+
+	scanner := bufio.NewScanner(stderr)
+
+	for scanner.Scan() {
+		slog.Info("providers", "external", e.path, "stderr", scanner.Text())
+	}
+}
+
+func (e *external) notification(msg rpcMessage) {
+	switch msg.Method {
+	case "updated":
+		var params struct {
+			Value string `json:"value"`
+		}
+
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			slog.Error("providers", "external", e.path, "updated", err)
+			return
+		}
+
+		if params.Value == "" {
+			params.Value = e.info.Name
+		}
+
+		e.mu.Lock()
+		updated := e.updated
+		e.mu.Unlock()
+
+		if updated != nil {
+			updated(params.Value)
+		}
+	default:
+		slog.Debug("providers", "external", e.path, "unknown notification", msg.Method)
+	}
+}
+
+func (e *external) write(req rpcRequest) error {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.stdin.Write(append(b, '\n'))
+
+	return err
+}
+
+// call sends a request and decodes the result into res, which can be nil. If ctx is done before
+// the response arrived, the provider is notified via "$/cancelRequest".
+func (e *external) call(ctx context.Context, method string, params, res any) error {
+	ch := make(chan rpcMessage, 1)
+
+	e.mu.Lock()
+
+	select {
+	case <-e.exited:
+		e.mu.Unlock()
+		return errExternalExited
+	default:
+	}
+
+	e.nextID++
+	id := e.nextID
+	e.pending[id] = ch
+
+	err := e.write(rpcRequest{JSONRPC: "2.0", ID: &id, Method: method, Params: params})
+	e.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	select {
+	case msg := <-ch:
+		if msg.Error != nil {
+			return msg.Error
+		}
+
+		if res == nil || len(msg.Result) == 0 {
+			return nil
+		}
+
+		return json.Unmarshal(msg.Result, res)
+	case <-e.exited:
+		return errExternalExited
+	case <-ctx.Done():
+		e.mu.Lock()
+		delete(e.pending, id)
+		e.mu.Unlock()
+
+		e.notify("$/cancelRequest", map[string]any{"id": id})
+
+		return ctx.Err()
+	case <-time.After(externalTimeout):
+		e.mu.Lock()
+		delete(e.pending, id)
+		e.mu.Unlock()
+
+		return fmt.Errorf("%s: timed out after %s", method, externalTimeout)
+	}
+}
+
+// notify sends a notification, no response is expected.
+func (e *external) notify(method string, params any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	select {
+	case <-e.exited:
+		return
+	default:
+	}
+
+	if err := e.write(rpcRequest{JSONRPC: "2.0", Method: method, Params: params}); err != nil {
+		slog.Error("providers", "external", e.path, method, err)
+	}
+}
+
+func (e *external) kill() {
+	e.stdin.Close()
+
+	if e.cmd.Process != nil {
+		e.cmd.Process.Kill()
+	}
+}
+
+func (e *external) APIVersion() int {
+	return e.info.APIVersion
+}
+
+func (e *external) Name() string {
+	return e.info.Name
+}
+
+func (e *external) NamePretty() string {
+	return e.info.NamePretty
+}
+
+func (e *external) Icon() string {
+	return e.info.Icon
+}
+
+func (e *external) PrintDoc() {
+	fmt.Printf("### %s\n", e.info.NamePretty)
+	fmt.Printf("External provider `%s`.\n", e.path)
+	fmt.Println()
+}
+
+func (e *external) Setup() error { return nil }
+
+func (e *external) Actions() []string {
+	return e.info.Actions
+}
+
+func (e *external) Subscribe(updated func(value string)) {
+	e.mu.Lock()
+	e.updated = updated
+	e.mu.Unlock()
+}
+
+func (e *external) Reload() error {
+	e.notify("reload", nil)
+
+	return nil
+}
+
+// Shutdown asks the provider to exit by sending "shutdown" and closing stdin, it's killed if it doesn't exit in time.
+func (e *external) Shutdown() {
+	e.notify("shutdown", nil)
+	e.stdin.Close()
+
+	select {
+	case <-e.exited:
+	case <-time.After(externalTimeout):
+		slog.Error("providers", "external", e.path, "shutdown", "killing")
+		e.kill()
+	}
+}
+
+func (e *external) Query(ctx context.Context, qid uint32, iid uint32, query string, single bool, exact bool) []*pb.QueryResponse_Item {
+	params := map[string]any{
+		"qid":    qid,
+		"iid":    iid,
+		"query":  query,
+		"single": single,
+		"exact":  exact,
+	}
+
+	var res []json.RawMessage
+
+	if err := e.call(ctx, "query", params, &res); err != nil {
+		if ctx.Err() == nil {
+			slog.Error(e.info.Name, "query", err)
+		}
+
+		return nil
+	}
+
+	entries := make([]*pb.QueryResponse_Item, 0, len(res))
+
+	for _, v := range res {
+		item := &pb.QueryResponse_Item{}
+
+		if err := protojson.Unmarshal(v, item); err != nil {
+			slog.Error(e.info.Name, "query", err)
+			continue
+		}
+
+		if item.Provider == "" {
+			item.Provider = e.info.Name
+		}
+
+		entries = append(entries, item)
+	}
+
+	return entries
+}
+
+func (e *external) Activate(qid uint32, identifier, action string, arguments string) error {
+	params := map[string]any{
+		"qid":        qid,
+		"identifier": identifier,
+		"action":     action,
+		"arguments":  arguments,
+	}
+
+	err := e.call(context.Background(), "activate", params, nil)
+	if err == nil {
+		return nil
+	}
+
+	var rpcErr *rpcError
+
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.Code {
+		case externalErrUnknownIdentifier:
+			return fmt.Errorf("%w: %s", provider.ErrUnknownIdentifier, rpcErr.Message)
+		case externalErrActionNotSupported:
+			return fmt.Errorf("%w: %s", provider.ErrActionNotSupported, rpcErr.Message)
+		case externalErrCommandFailed:
+			return fmt.Errorf("%w: %s", provider.ErrCommandFailed, rpcErr.Message)
+		}
+	}
+
+	return err
+}
+
+func (e *external) Cleanup(qid uint32) {
+	e.notify("cleanup", map[string]any{"qid": qid})
+}
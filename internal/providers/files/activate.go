@@ -1,9 +1,8 @@
-package main
+package files
 
 import (
 	"fmt"
 	"log"
-	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -11,6 +10,8 @@ import (
 	"syscall"
 
 	"github.com/abenz1267/elephant/internal/common"
+	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"github.com/abenz1267/elephant/pkg/provider"
 )
 
 const (
@@ -20,8 +21,28 @@ const (
 	ActionCopyFile = "copyfile"
 )
 
-func Activate(qid uint32, identifier, action string, arguments string) {
-	path := paths[identifier].path
+// Actions returns the actions supported by Activate.
+func (plugin) Actions() []string {
+	return []string{ActionOpen, ActionOpenDir, ActionCopyPath, ActionCopyFile}
+}
+
+var itemActions = []*pb.QueryResponse_Item_Action{
+	{Name: ActionOpen, Label: "Open", Icon: "document-open", Default: true},
+	{Name: ActionOpenDir, Label: "Open Folder", Icon: "folder-open"},
+	{Name: ActionCopyPath, Label: "Copy Path", Icon: "edit-copy"},
+	{Name: ActionCopyFile, Label: "Copy File", Icon: "edit-copy"},
+}
+
+func (plugin) Activate(qid uint32, identifier, action string, arguments string) error {
+	pm.Lock()
+	f, ok := paths[identifier]
+	pm.Unlock()
+
+	if !ok {
+		return provider.ErrUnknownIdentifier
+	}
+
+	path := f.path
 
 	if action == "" {
 		action = ActionOpen
@@ -33,7 +54,7 @@ func Activate(qid uint32, identifier, action string, arguments string) {
 			path = filepath.Dir(path)
 		}
 
-		run := strings.TrimSpace(fmt.Sprintf("%s xdg-open '%s'", common.LaunchPrefix(config.LaunchPrefix), path))
+		run := strings.TrimSpace(fmt.Sprintf("%s xdg-open '%s'", common.LaunchPrefix(config.Load().LaunchPrefix), path))
 
 		if forceTerminalForFile(path) {
 			run = common.WrapWithTerminal(run)
@@ -46,38 +67,40 @@ func Activate(qid uint32, identifier, action string, arguments string) {
 
 		err := cmd.Start()
 		if err != nil {
-			slog.Error(Name, "actionopen", err)
-		} else {
-			go func() {
-				cmd.Wait()
-			}()
+			return fmt.Errorf("%w: %w", provider.ErrCommandFailed, err)
 		}
+
+		go func() {
+			cmd.Wait()
+		}()
 	case ActionCopyPath:
 		cmd := exec.Command("wl-copy", path)
 
 		err := cmd.Start()
 		if err != nil {
-			slog.Error(Name, "actioncopypath", err)
-		} else {
-			go func() {
-				cmd.Wait()
-			}()
+			return fmt.Errorf("%w: %w", provider.ErrCommandFailed, err)
 		}
 
+		go func() {
+			cmd.Wait()
+		}()
+
 	case ActionCopyFile:
 		cmd := exec.Command("wl-copy", "-t", "text/uri-list", fmt.Sprintf("file://%s", path))
 
 		err := cmd.Start()
 		if err != nil {
-			slog.Error(Name, "actioncopyfile", err)
-		} else {
-			go func() {
-				cmd.Wait()
-			}()
+			return fmt.Errorf("%w: %w", provider.ErrCommandFailed, err)
 		}
+
+		go func() {
+			cmd.Wait()
+		}()
 	default:
-		slog.Error(Name, "nosuchaction", action)
+		return fmt.Errorf("%w: %s", provider.ErrActionNotSupported, action)
 	}
+
+	return nil
 }
 
 func forceTerminalForFile(file string) bool {
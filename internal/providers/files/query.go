@@ -1,6 +1,7 @@
-package main
+package files
 
 import (
+	"context"
 	"log/slog"
 	"strings"
 	"time"
@@ -9,7 +10,7 @@ import (
 	"github.com/abenz1267/elephant/pkg/pb/pb"
 )
 
-func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.QueryResponse_Item {
+func (plugin) Query(_ context.Context, qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.QueryResponse_Item {
 	start := time.Now()
 
 	initialCap := len(paths)
@@ -32,6 +33,7 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 					Subtext:    "",
 					Provider:   Name,
 					Score:      score,
+					Actions:    itemActions,
 					Fuzzyinfo: &pb.QueryResponse_Item_FuzzyInfo{
 						Start:     s,
 						Field:     "text",
@@ -51,6 +53,7 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 					Subtext:    "",
 					Provider:   Name,
 					Score:      score,
+					Actions:    itemActions,
 					Fuzzyinfo: &pb.QueryResponse_Item_FuzzyInfo{
 						Start:     0,
 						Field:     "text",
@@ -1,4 +1,5 @@
-package main
+// Package files provides access to files in $HOME.
+package files
 
 import (
 	"bytes"
@@ -6,18 +7,19 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io/fs"
-	"log"
 	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/abenz1267/elephant/internal/common"
 	"github.com/abenz1267/elephant/internal/providers"
 	"github.com/abenz1267/elephant/internal/util"
+	"github.com/abenz1267/elephant/pkg/provider"
 	"github.com/adrg/xdg"
 	"github.com/charlievieth/fastwalk"
 	"github.com/djherbis/times"
@@ -41,41 +43,57 @@ var terminalApps = make(map[string]struct{})
 var (
 	Name       = "files"
 	NamePretty = "Files"
-	config     *Config
+	config     atomic.Pointer[Config]
 )
 
+type plugin struct{}
+
+// Provider is looked up by elephant when loading the plugin.
+var Provider provider.Provider = plugin{}
+
+func (plugin) APIVersion() int {
+	return provider.APIVersion
+}
+
+func (plugin) Name() string {
+	return Name
+}
+
+func (plugin) NamePretty() string {
+	return NamePretty
+}
+
 type Config struct {
 	common.Config `koanf:",squash"`
 	LaunchPrefix  string `koanf:"launch_prefix" desc:"overrides the default app2unit or uwsm prefix, if set. 'CLEAR' to not prefix." default:""`
 }
 
-func init() {
+func (plugin) Setup() error {
 	start := time.Now()
 
-	config = &Config{
-		Config: common.Config{
-			Icon:     "folder",
-			MinScore: 50,
-		},
-		LaunchPrefix: "",
+	c := defaultConfig()
+
+	if err := common.ReadConfig(Name, c); err != nil {
+		return err
 	}
 
-	common.LoadConfig(Name, config)
+	config.Store(c)
 
-	findTerminalApps()
+	if err := findTerminalApps(); err != nil {
+		return err
+	}
 
 	home, _ := os.UserHomeDir()
 	cmd := exec.Command("fd", ".", home, "--ignore-vcs", "--type", "file", "--type", "directory")
 
 	out, err := cmd.CombinedOutput()
 	if err != nil {
-		slog.Error(Name, "files", err)
-		os.Exit(1)
+		return fmt.Errorf("fd: %w", err)
 	}
 
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	go func() {
@@ -168,23 +186,51 @@ func init() {
 	}
 
 	slog.Info(Name, "files", len(paths), "time", time.Since(start))
+
+	return nil
 }
 
-func PrintDoc() {
+func (plugin) DefaultConfig() any {
+	return defaultConfig()
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Config: common.Config{
+			Icon:     "folder",
+			MinScore: 50,
+		},
+		LaunchPrefix: "",
+	}
+}
+
+func (plugin) Reload() error {
+	c := defaultConfig()
+
+	if err := common.ReadConfig(Name, c); err != nil {
+		return err
+	}
+
+	config.Store(c)
+
+	return nil
+}
+
+func (plugin) PrintDoc() {
 	fmt.Printf("### %s\n", NamePretty)
 	fmt.Println("Search files and folders.")
 	fmt.Println()
 	util.PrintConfig(Config{}, Name)
 }
 
-func Cleanup(qid uint32) {
+func (plugin) Cleanup(qid uint32) {
 	slog.Info(Name, "cleanup", qid)
 	results.Lock()
 	delete(results.Queries, qid)
 	results.Unlock()
 }
 
-func findTerminalApps() {
+func findTerminalApps() error {
 	conf := fastwalk.Config{
 		Follow: true,
 	}
@@ -207,12 +253,13 @@ func findTerminalApps() {
 			}
 			return nil
 		}); err != nil {
-			slog.Error(Name, "walk", err)
-			os.Exit(1)
+			return err
 		}
 	}
+
+	return nil
 }
 
-func Icon() string {
-	return config.Icon
+func (plugin) Icon() string {
+	return config.Load().Icon
 }
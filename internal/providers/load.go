@@ -2,6 +2,7 @@
 package providers
 
 import (
+	"fmt"
 	"io/fs"
 	"log/slog"
 	"os"
@@ -13,25 +14,25 @@ import (
 
 	"github.com/abenz1267/elephant/internal/common"
 	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"github.com/abenz1267/elephant/pkg/provider"
 	"github.com/charlievieth/fastwalk"
 )
 
-type Provider struct {
-	Name       *string
-	NamePretty *string
-	PrintDoc   func()
-	Icon       func() string
-	Cleanup    func(qid uint32)
-	Activate   func(qid uint32, identifier, action string, arguments string)
-	Query      func(qid uint32, iid uint32, query string, single bool, exact bool) []*pb.QueryResponse_Item
-}
-
 var (
-	Providers      map[string]Provider
+	Providers      map[string]provider.Provider
 	QueryProviders map[uint32][]string
 	AsyncChannels  = make(map[uint32]map[uint32]chan *pb.QueryResponse_Item)
+	// OnUpdate is passed to providers implementing provider.Subscriber.
+	OnUpdate = func(value string) {}
+
+	builtin []provider.Provider
 )
 
+// Register adds a provider compiled into the binary. It has to be called before Load, f.e. in init().
+func Register(p provider.Provider) {
+	builtin = append(builtin, p)
+}
+
 func Load() {
 	start := time.Now()
 	common.LoadMenus()
@@ -41,11 +42,38 @@ func Load() {
 	have := []string{}
 	dirs := []string{filepath.Join(common.ConfigDir(), "providers"), "/etc/xdg/elephant/providers"}
 
-	Providers = make(map[string]Provider)
+	Providers = make(map[string]provider.Provider)
 	QueryProviders = make(map[uint32][]string)
 
-	for _, v := range dirs {
-		if !common.FileExists(v) {
+	var wg sync.WaitGroup
+
+	// all built-in providers are added before any setup runs, failed setups remove them concurrently
+	for _, p := range builtin {
+		Providers[p.Name()] = p
+		// plugins of built-in providers can't be loaded anyways, as the packages are already part of the binary.
+		have = append(have, p.Name()+".so")
+	}
+
+	for _, p := range builtin {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := setup(p); err != nil {
+				slog.Error("providers", "skipping", p.Name(), "reason", err)
+
+				mut.Lock()
+				delete(Providers, p.Name())
+				mut.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for _, root := range dirs {
+		if !common.FileExists(root) {
 			continue
 		}
 
@@ -63,68 +91,54 @@ func Load() {
 			done := slices.Contains(have, filepath.Base(path))
 			mut.Unlock()
 
-			if !done && filepath.Ext(path) == ".so" {
-				p, err := plugin.Open(path)
-				if err != nil {
-					panic(err)
-				}
-
-				name, err := p.Lookup("Name")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
-				}
+			if done || d.IsDir() {
+				return nil
+			}
 
-				namePretty, err := p.Lookup("NamePretty")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
-				}
+			var p provider.Provider
 
-				activateFunc, err := p.Lookup("Activate")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
-				}
+			switch {
+			case filepath.Ext(path) == ".so":
+				p, err = open(path)
+			case isExternal(root, path):
+				p, err = openExternal(path)
+			default:
+				return nil
+			}
 
-				queryFunc, err := p.Lookup("Query")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
-				}
+			if err != nil {
+				slog.Error("providers", "skipping", path, "reason", err)
+				return nil
+			}
 
-				cleanupFunc, err := p.Lookup("Cleanup")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
-				}
+			mut.Lock()
+			if _, ok := Providers[p.Name()]; ok {
+				mut.Unlock()
+				slog.Error("providers", "skipping", path, "reason", fmt.Sprintf("provider '%s' already loaded", p.Name()))
 
-				iconFunc, err := p.Lookup("Icon")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
+				if e, ok := p.(*external); ok {
+					e.kill()
 				}
 
-				printDocFunc, err := p.Lookup("PrintDoc")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
-				}
+				return nil
+			}
 
-				provider := Provider{
-					Icon:       iconFunc.(func() string),
-					Name:       name.(*string),
-					Cleanup:    cleanupFunc.(func(uint32)),
-					Activate:   activateFunc.(func(uint32, string, string, string)),
-					Query:      queryFunc.(func(uint32, uint32, string, bool, bool) []*pb.QueryResponse_Item),
-					NamePretty: namePretty.(*string),
-					PrintDoc:   printDocFunc.(func()),
-				}
+			Providers[p.Name()] = p
+			have = append(have, filepath.Base(path))
+			mut.Unlock()
 
-				Providers[*provider.Name] = provider
+			if err := setup(p); err != nil {
+				slog.Error("providers", "skipping", path, "reason", err)
 
 				mut.Lock()
-				have = append(have, filepath.Base(path))
+				delete(Providers, p.Name())
 				mut.Unlock()
 			}
 
-			return err
+			return nil
 		}
 
-		if err := fastwalk.Walk(&conf, v, walkFn); err != nil {
+		if err := fastwalk.Walk(&conf, root, walkFn); err != nil {
 			slog.Error("providers", "load", err)
 			os.Exit(1)
 		}
@@ -137,3 +151,67 @@ func Load() {
 
 	slog.Info("providers", "loaded", len(Providers), "time", time.Since(start))
 }
+
+// open loads a plugin and validates the exported provider.
+func open(path string) (provider.Provider, error) {
+	plug, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sym, err := plug.Lookup(provider.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("symbol '%s' not found, plugin was probably built for an older version of elephant", provider.Symbol)
+	}
+
+	var p provider.Provider
+
+	switch v := sym.(type) {
+	case *provider.Provider:
+		p = *v
+	case provider.Provider:
+		p = v
+	default:
+		return nil, fmt.Errorf("symbol '%s' has type %T, expected provider.Provider", provider.Symbol, sym)
+	}
+
+	if p == nil {
+		return nil, fmt.Errorf("symbol '%s' is nil", provider.Symbol)
+	}
+
+	if p.APIVersion() != provider.APIVersion {
+		return nil, fmt.Errorf("provider API version %d, expected %d", p.APIVersion(), provider.APIVersion)
+	}
+
+	return p, nil
+}
+
+// setup runs the providers setup, a panicking provider won't take down elephant.
+func setup(p provider.Provider) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("setup panicked: %v", r)
+		}
+	}()
+
+	if err := p.Setup(); err != nil {
+		return err
+	}
+
+	if s, ok := p.(provider.Subscriber); ok {
+		s.Subscribe(func(value string) {
+			OnUpdate(value)
+		})
+	}
+
+	return nil
+}
+
+// Actions returns the actions supported by the provider.
+func Actions(p provider.Provider) []string {
+	if a, ok := p.(provider.Actioner); ok {
+		return a.Actions()
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/abenz1267/elephant/internal/common"
+	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"github.com/abenz1267/elephant/pkg/provider"
+)
+
+type testProvider struct {
+	name  string
+	setup func() error
+}
+
+func (p *testProvider) APIVersion() int    { return provider.APIVersion }
+func (p *testProvider) Name() string       { return p.name }
+func (p *testProvider) NamePretty() string { return p.name }
+func (p *testProvider) Icon() string       { return "" }
+func (p *testProvider) PrintDoc()          {}
+func (p *testProvider) Setup() error       { return p.setup() }
+func (p *testProvider) Cleanup(uint32)     {}
+
+func (p *testProvider) Query(context.Context, uint32, uint32, string, bool, bool) []*pb.QueryResponse_Item {
+	return nil
+}
+
+func (p *testProvider) Activate(uint32, string, string, string) error {
+	return nil
+}
+
+// setupTest replaces the built-in providers and uses an empty config dir.
+func setupTest(t *testing.T, p ...provider.Provider) {
+	t.Helper()
+
+	common.SetExplicitDir(t.TempDir())
+
+	prev := builtin
+	builtin = p
+
+	t.Cleanup(func() {
+		builtin = prev
+		common.SetExplicitDir("")
+	})
+}
+
+func TestLoadSkipsFailingSetup(t *testing.T) {
+	setupTest(t,
+		&testProvider{name: "ok", setup: func() error { return nil }},
+		&testProvider{name: "failing", setup: func() error { return errors.New("missing dependency") }},
+		&testProvider{name: "panicking", setup: func() error { panic("oops") }},
+	)
+
+	Load()
+
+	names := []string{}
+
+	for k := range Providers {
+		names = append(names, k)
+	}
+
+	if !slices.Equal(names, []string{"ok"}) {
+		t.Errorf("got %v, want [ok]", names)
+	}
+}
+
+func TestLoadSubscribes(t *testing.T) {
+	p := &subscribingProvider{testProvider: testProvider{name: "subscribing", setup: func() error { return nil }}}
+	setupTest(t, p)
+
+	var got string
+
+	prev := OnUpdate
+	OnUpdate = func(value string) { got = value }
+	t.Cleanup(func() { OnUpdate = prev })
+
+	Load()
+
+	if p.updated == nil {
+		t.Fatal("Subscribe wasn't called")
+	}
+
+	p.updated("subscribing")
+
+	if got != "subscribing" {
+		t.Errorf("got %q, want %q", got, "subscribing")
+	}
+}
+
+type subscribingProvider struct {
+	testProvider
+	updated func(value string)
+}
+
+func (p *subscribingProvider) Subscribe(updated func(value string)) {
+	p.updated = updated
+}
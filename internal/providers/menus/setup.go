@@ -1,6 +1,8 @@
-package main
+// Package menus provides user defined menus.
+package menus
 
 import (
+	"context"
 	_ "embed"
 	"fmt"
 	"log/slog"
@@ -9,28 +11,60 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/abenz1267/elephant/internal/comm/handlers"
 	"github.com/abenz1267/elephant/internal/common"
 	"github.com/abenz1267/elephant/internal/providers"
 	"github.com/abenz1267/elephant/internal/util"
 	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"github.com/abenz1267/elephant/pkg/provider"
 )
 
 var (
 	Name       = "menus"
 	NamePretty = "Menus"
+	updated    = func(value string) {}
 )
 
+type plugin struct{}
+
+// Provider is looked up by elephant when loading the plugin.
+var Provider provider.Provider = plugin{}
+
+func (plugin) APIVersion() int {
+	return provider.APIVersion
+}
+
+func (plugin) Name() string {
+	return Name
+}
+
+func (plugin) NamePretty() string {
+	return NamePretty
+}
+
 //go:embed other.toml
 var other string
 
 //go:embed screenshots.toml
 var screenshots string
 
-func PrintDoc() {
+const (
+	ActionRun  = "run"
+	ActionOpen = "open"
+)
+
+// Actions returns the actions supported by Activate.
+func (plugin) Actions() []string {
+	return []string{ActionRun, ActionOpen}
+}
+
+var openActions = []*pb.QueryResponse_Item_Action{
+	{Name: ActionOpen, Label: "Open", Icon: "go-next", Default: true},
+}
+
+func (plugin) PrintDoc() {
 	fmt.Printf("### %s\n", NamePretty)
 	fmt.Println("Custom menus.")
-	fmt.Println("Default location for menu definitions is `~/.config/elephant/menus/`.")
+	fmt.Println("Default location for menu definitions is `~/.config/elephant/menus/`, only `.toml` files are loaded. Changes are picked up automatically.")
 	util.PrintConfig(common.MenuConfig{}, Name)
 	fmt.Println("#### Example Menus")
 	fmt.Println()
@@ -40,10 +74,28 @@ func PrintDoc() {
 	fmt.Println()
 }
 
-func Cleanup(qid uint32) {
+// Setup starts watching the menu definitions, menus themselves are loaded by elephant.
+func (plugin) Setup() error {
+	watch()
+
+	return nil
+}
+
+// Reload watches paths added to the config, menus themselves are reloaded by elephant.
+func (plugin) Reload() error {
+	watchPaths()
+
+	return nil
+}
+
+func (plugin) Subscribe(fn func(value string)) {
+	updated = fn
+}
+
+func (plugin) Cleanup(qid uint32) {
 }
 
-func Activate(qid uint32, identifier, action string, arguments string) {
+func (plugin) Activate(qid uint32, identifier, action string, arguments string) error {
 	var e common.Entry
 	var menu common.Menu
 
@@ -57,7 +109,7 @@ func Activate(qid uint32, identifier, action string, arguments string) {
 
 	openmenu := false
 
-	for _, v := range common.Menus {
+	for _, v := range common.Menus() {
 		if identifier == v.Name {
 			menu = v
 			openmenu = true
@@ -74,8 +126,20 @@ func Activate(qid uint32, identifier, action string, arguments string) {
 	}
 
 	if openmenu {
-		handlers.ProviderUpdated <- fmt.Sprintf("%s:%s", Name, menu.Name)
-		return
+		if action != "" && action != ActionOpen {
+			return fmt.Errorf("%w: %s", provider.ErrActionNotSupported, action)
+		}
+
+		updated(fmt.Sprintf("%s:%s", Name, menu.Name))
+		return nil
+	}
+
+	if menu.Name == "" {
+		return provider.ErrUnknownIdentifier
+	}
+
+	if action != "" && action != ActionRun {
+		return fmt.Errorf("%w: %s", provider.ErrActionNotSupported, action)
 	}
 
 	run := menu.Action
@@ -85,7 +149,7 @@ func Activate(qid uint32, identifier, action string, arguments string) {
 	}
 
 	if run == "" {
-		return
+		return nil
 	}
 
 	pipe := false
@@ -113,15 +177,17 @@ func Activate(qid uint32, identifier, action string, arguments string) {
 
 	err := cmd.Start()
 	if err != nil {
-		slog.Error(Name, "activate", err)
-	} else {
-		go func() {
-			cmd.Wait()
-		}()
+		return fmt.Errorf("%w: %w", provider.ErrCommandFailed, err)
 	}
+
+	go func() {
+		cmd.Wait()
+	}()
+
+	return nil
 }
 
-func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.QueryResponse_Item {
+func (plugin) Query(ctx context.Context, qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.QueryResponse_Item {
 	start := time.Now()
 	entries := []*pb.QueryResponse_Item{}
 	menu := ""
@@ -134,7 +200,7 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 		query = split[1]
 	}
 
-	for _, v := range common.Menus {
+	for _, v := range common.Menus() {
 		if menu != "" && v.Name != menu || (!single && !v.GlobalSearch) {
 			continue
 		}
@@ -154,13 +220,30 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 				Icon:       icon,
 				Type:       pb.QueryResponse_REGULAR,
 				Preview:    v.Preview,
+				Actions:    openActions,
+			}
+
+			if v.SubMenu == "" {
+				e.Actions = entryActions(v)
 			}
 
 			if v.Async != "" {
 				go func() {
-					cmd := exec.Command("sh", "-c", v.Async)
+					cmd := exec.CommandContext(ctx, "sh", "-c", v.Async)
+					// kill the whole process group, not just the shell
+					cmd.SysProcAttr = &syscall.SysProcAttr{
+						Setpgid: true,
+					}
+					cmd.Cancel = func() error {
+						return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+					}
+
 					out, err := cmd.CombinedOutput()
 
+					if ctx.Err() != nil {
+						return
+					}
+
 					if err == nil {
 						e.Text = strings.TrimSpace(string(out))
 					} else {
@@ -179,7 +262,7 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 				e.Score, e.Fuzzyinfo.Positions, e.Fuzzyinfo.Start = common.FuzzyScore(query, e.Text, exact)
 			}
 
-			if e.Score > common.MenuConfigLoaded.MinScore || query == "" {
+			if e.Score > common.GetMenuConfig().MinScore || query == "" {
 				entries = append(entries, e)
 			}
 		}
@@ -190,6 +273,23 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 	return entries
 }
 
-func Icon() string {
+func entryActions(e common.Entry) []*pb.QueryResponse_Item_Action {
+	run := e.Action
+	if run == "" {
+		run = common.Menus()[e.Menu].Action
+	}
+
+	return []*pb.QueryResponse_Item_Action{
+		{
+			Name:             ActionRun,
+			Label:            "Run",
+			Icon:             "system-run",
+			Default:          true,
+			RequiresArgument: e.Value == "" && strings.Contains(run, "%RESULT%"),
+		},
+	}
+}
+
+func (plugin) Icon() string {
 	return ""
 }
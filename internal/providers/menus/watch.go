@@ -0,0 +1,193 @@
+package menus
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abenz1267/elephant/internal/common"
+	"github.com/charlievieth/fastwalk"
+	"github.com/fsnotify/fsnotify"
+)
+
+const debounce = 100 * time.Millisecond
+
+var (
+	watcher   *fsnotify.Watcher
+	watched   = make(map[string]bool)
+	watchedMu sync.Mutex
+	pending   = make(map[string]*time.Timer)
+	pendingMu sync.Mutex
+)
+
+// watch starts watching all menu paths, changed menus are re-parsed and pushed to subscribers.
+func watch() {
+	var err error
+
+	watcher, err = fsnotify.NewWatcher()
+	if err != nil {
+		slog.Error(Name, "watcher", err)
+		return
+	}
+
+	watchPaths()
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				handleEvent(event)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				slog.Error(Name, "watcher", err)
+			}
+		}
+	}()
+}
+
+// watchPaths adds all configured menu paths and their subdirectories to the watcher.
+func watchPaths() {
+	if watcher == nil {
+		return
+	}
+
+	for _, root := range common.GetMenuConfig().Paths {
+		if _, err := os.Stat(root); err != nil {
+			continue
+		}
+
+		walkDirs(root, nil)
+	}
+}
+
+// walkDirs watches dir and all directories below it, fn is called for every menu file found.
+func walkDirs(dir string, fn func(path string)) {
+	conf := fastwalk.Config{
+		Follow: true,
+	}
+
+	if err := fastwalk.Walk(&conf, dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if d.IsDir() {
+			watchedMu.Lock()
+			defer watchedMu.Unlock()
+
+			if watched[path] {
+				return nil
+			}
+
+			if err := watcher.Add(path); err != nil {
+				slog.Warn(Name, "watcher_add", err, "dir", path)
+				return nil
+			}
+
+			watched[path] = true
+
+			return nil
+		}
+
+		if fn != nil && common.IsMenuFile(path) {
+			fn(path)
+		}
+
+		return nil
+	}); err != nil {
+		slog.Error(Name, "walk", err)
+	}
+}
+
+func handleEvent(event fsnotify.Event) {
+	slog.Debug(Name, "file_system_event", event)
+
+	if common.IsMenuFile(event.Name) {
+		schedule(event.Name)
+		return
+	}
+
+	switch {
+	case event.Has(fsnotify.Create):
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			// files might have been created before the directory was watched
+			walkDirs(event.Name, schedule)
+		}
+	case event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename):
+		unwatch(event.Name, event.Has(fsnotify.Rename))
+
+		notify(common.RemoveMenuFile(event.Name))
+	}
+}
+
+// unwatch forgets dir and all directories below it. Renamed directories are still watched by the kernel and would
+// report events under their old path, so their watches are removed.
+func unwatch(dir string, renamed bool) {
+	watchedMu.Lock()
+	defer watchedMu.Unlock()
+
+	for k := range watched {
+		if k != dir && !strings.HasPrefix(k, dir+string(filepath.Separator)) {
+			continue
+		}
+
+		if renamed {
+			watcher.Remove(k)
+		}
+
+		delete(watched, k)
+	}
+}
+
+// schedule handles changes of a menu file once it stopped changing, editors usually write files in multiple steps.
+func schedule(path string) {
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+
+	if t, ok := pending[path]; ok {
+		t.Reset(debounce)
+		return
+	}
+
+	pending[path] = time.AfterFunc(debounce, func() {
+		pendingMu.Lock()
+		delete(pending, path)
+		pendingMu.Unlock()
+
+		if _, err := os.Stat(path); err != nil {
+			notify(common.RemoveMenuFile(path))
+			return
+		}
+
+		updateFile(path)
+	})
+}
+
+func updateFile(path string) {
+	changed, err := common.UpdateMenuFile(path)
+	if err != nil {
+		slog.Error(Name, "watch", err)
+		return
+	}
+
+	notify(changed)
+}
+
+func notify(menus []string) {
+	for _, v := range menus {
+		slog.Info(Name, "updated", v)
+		updated(fmt.Sprintf("%s:%s", Name, v))
+	}
+}
@@ -0,0 +1,56 @@
+package menus
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestUnwatchRemovesSubdirectories(t *testing.T) {
+	root := t.TempDir()
+
+	for _, v := range []string{"a/b/c", "ab"} {
+		if err := os.MkdirAll(filepath.Join(root, v), 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var err error
+
+	watcher, err = fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		watcher.Close()
+		watcher = nil
+		clear(watched)
+	})
+
+	walkDirs(root, nil)
+	unwatch(filepath.Join(root, "a"), true)
+
+	want := []string{root, filepath.Join(root, "ab")}
+	got := []string{}
+
+	for k := range watched {
+		got = append(got, k)
+	}
+
+	slices.Sort(got)
+
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	list := watcher.WatchList()
+	slices.Sort(list)
+
+	if !slices.Equal(list, want) {
+		t.Errorf("watcher: got %v, want %v", list, want)
+	}
+}
@@ -1,61 +1,111 @@
-package main
+// Package providerlist lists all loaded providers and menus.
+package providerlist
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/abenz1267/elephant/internal/common"
 	"github.com/abenz1267/elephant/internal/providers"
 	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"github.com/abenz1267/elephant/pkg/provider"
 )
 
 var (
 	Name       = "providerlist"
 	NamePretty = "Providerlist"
-	config     *Config
+	config     atomic.Pointer[Config]
 )
 
+type plugin struct{}
+
+// Provider is looked up by elephant when loading the plugin.
+var Provider provider.Provider = plugin{}
+
+func (plugin) APIVersion() int {
+	return provider.APIVersion
+}
+
+func (plugin) Name() string {
+	return Name
+}
+
+func (plugin) NamePretty() string {
+	return NamePretty
+}
+
 type Config struct {
 	common.Config `koanf:",squash"`
 }
 
-func init() {
-	config = &Config{
+func (plugin) Setup() error {
+	c := defaultConfig()
+
+	if err := common.ReadConfig(Name, c); err != nil {
+		return err
+	}
+
+	config.Store(c)
+
+	return nil
+}
+
+func (plugin) DefaultConfig() any {
+	return defaultConfig()
+}
+
+func defaultConfig() *Config {
+	return &Config{
 		Config: common.Config{
 			Icon:     "applications-other",
 			MinScore: 10,
 		},
 	}
+}
 
-	common.LoadConfig(Name, config)
+func (plugin) Reload() error {
+	c := defaultConfig()
+
+	if err := common.ReadConfig(Name, c); err != nil {
+		return err
+	}
+
+	config.Store(c)
+
+	return nil
 }
 
-func PrintDoc() {
+func (plugin) PrintDoc() {
 	fmt.Printf("### %s\n", NamePretty)
 	fmt.Println("List installed providers")
 	fmt.Println()
 }
 
-func Cleanup(qid uint32) {
+func (plugin) Cleanup(qid uint32) {
 }
 
-func Activate(qid uint32, identifier, action string, arguments string) {
+func (plugin) Activate(qid uint32, identifier, action string, arguments string) error {
+	return nil
 }
 
-func Query(qid uint32, iid uint32, query string, single bool, exact bool) []*pb.QueryResponse_Item {
+func (plugin) Query(_ context.Context, qid uint32, iid uint32, query string, single bool, exact bool) []*pb.QueryResponse_Item {
+	cfg := config.Load()
+
 	start := time.Now()
 	entries := []*pb.QueryResponse_Item{}
 
 	for _, v := range providers.Providers {
-		if *v.Name == Name {
+		if v.Name() == Name {
 			continue
 		}
 
-		if *v.Name == "menus" {
-			for _, v := range common.Menus {
+		if v.Name() == "menus" {
+			for _, v := range common.Menus() {
 				if v.HideFromProviderlist {
 					continue
 				}
@@ -77,14 +127,14 @@ func Query(qid uint32, iid uint32, query string, single bool, exact bool) []*pb.
 					e.Score, e.Fuzzyinfo.Positions, e.Fuzzyinfo.Start = common.FuzzyScore(query, e.Text, exact)
 				}
 
-				if e.Score > config.MinScore || query == "" {
+				if e.Score > cfg.MinScore || query == "" {
 					entries = append(entries, e)
 				}
 			}
 		} else {
 			e := &pb.QueryResponse_Item{
-				Identifier: *v.Name,
-				Text:       *v.NamePretty,
+				Identifier: v.Name(),
+				Text:       v.NamePretty(),
 				Icon:       v.Icon(),
 				Provider:   Name,
 				Type:       pb.QueryResponse_REGULAR,
@@ -98,7 +148,7 @@ func Query(qid uint32, iid uint32, query string, single bool, exact bool) []*pb.
 				e.Score, e.Fuzzyinfo.Positions, e.Fuzzyinfo.Start = common.FuzzyScore(query, e.Text, exact)
 			}
 
-			if e.Score > config.MinScore || query == "" {
+			if e.Score > cfg.MinScore || query == "" {
 				entries = append(entries, e)
 			}
 		}
@@ -121,6 +171,6 @@ func Query(qid uint32, iid uint32, query string, single bool, exact bool) []*pb.
 	return entries
 }
 
-func Icon() string {
+func (plugin) Icon() string {
 	return ""
 }
@@ -0,0 +1,67 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/abenz1267/elephant/internal/common"
+	"github.com/abenz1267/elephant/internal/systemd"
+	"github.com/abenz1267/elephant/pkg/provider"
+)
+
+// Reload re-reads elephant.toml, the menus and all provider configs and notifies subscribers.
+// Configs that fail to load are kept as they are, the returned error lists all failures.
+func Reload() error {
+	start := time.Now()
+
+	systemd.Reloading()
+	defer systemd.Ready()
+
+	var errs []error
+
+	if err := common.ReloadGlobalConfig(); err != nil {
+		errs = append(errs, fmt.Errorf("elephant: %w", err))
+	}
+
+	if err := common.ReloadMenus(); err != nil {
+		errs = append(errs, fmt.Errorf("menus: %w", err))
+	}
+
+	for _, p := range Providers {
+		if r, ok := p.(provider.Reloader); ok {
+			if err := reload(r); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			}
+		}
+	}
+
+	for _, p := range Providers {
+		OnUpdate(p.Name())
+	}
+
+	for k := range common.Menus() {
+		OnUpdate(fmt.Sprintf("menus:%s", k))
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		slog.Error("providers", "reload", err)
+	}
+
+	slog.Info("providers", "reloaded", len(Providers), "time", time.Since(start))
+
+	return err
+}
+
+// reload runs the providers reload, a panicking provider won't take down elephant.
+func reload(r provider.Reloader) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("reload panicked: %v", r)
+		}
+	}()
+
+	return r.Reload()
+}
@@ -1,7 +1,8 @@
 // Package runner provides access to binaries in $PATH.
-package main
+package runner
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -20,6 +22,7 @@ import (
 	"github.com/abenz1267/elephant/internal/providers"
 	"github.com/abenz1267/elephant/internal/util"
 	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"github.com/abenz1267/elephant/pkg/provider"
 )
 
 var (
@@ -28,6 +31,23 @@ var (
 	results    = providers.QueryData{}
 )
 
+type plugin struct{}
+
+// Provider is looked up by elephant when loading the plugin.
+var Provider provider.Provider = plugin{}
+
+func (plugin) APIVersion() int {
+	return provider.APIVersion
+}
+
+func (plugin) Name() string {
+	return Name
+}
+
+func (plugin) NamePretty() string {
+	return NamePretty
+}
+
 type ExplicitItem struct {
 	Exec  string `koanf:"exec" desc:"executable/command to run" default:""`
 	Alias string `koanf:"alias" desc:"alias" default:""`
@@ -35,14 +55,17 @@ type ExplicitItem struct {
 
 type Config struct {
 	common.Config `koanf:",squash"`
-	History       bool           `koanf:"history" desc:"make use of history for sorting" default:"false"`
-	Explicits     []ExplicitItem `koanf:"explicits" desc:"use this explicit list, instead of searching $PATH" default:""`
+	History       bool            `koanf:"history" desc:"make use of history for sorting" default:"false"`
+	Scoring       history.Scoring `koanf:"scoring" desc:"how history is used for sorting" default:""`
+	Explicits     []ExplicitItem  `koanf:"explicits" desc:"use this explicit list, instead of searching $PATH" default:""`
 }
 
 var (
 	config *Config
 	items  = []Item{}
-	h      = history.Load(Name)
+	// mu guards config and items, both are replaced on reload
+	mu sync.RWMutex
+	h  *history.History
 )
 
 type Item struct {
@@ -51,20 +74,89 @@ type Item struct {
 	Alias      string
 }
 
-func init() {
+func (plugin) Setup() error {
 	start := time.Now()
+	h = history.Load(Name)
+
+	config = defaultConfig()
+
+	if err := common.ReadConfig(Name, config); err != nil {
+		return err
+	}
+
+	if err := h.SetScoring(config.Scoring); err != nil {
+		slog.Error(Name, "scoring", err)
+	}
+
+	items = loadItems(config)
 
-	config = &Config{
+	migrate()
+
+	slog.Info(Name, "executables", len(items), "time", time.Since(start))
+
+	return nil
+}
+
+func (plugin) DefaultConfig() any {
+	return defaultConfig()
+}
+
+func defaultConfig() *Config {
+	return &Config{
 		Config: common.Config{
 			Icon:     "utilities-terminal",
 			MinScore: 50,
 		},
 		History: true,
+		Scoring: history.DefaultScoring(),
+	}
+}
+
+func (plugin) Reload() error {
+	c := defaultConfig()
+
+	if err := common.ReadConfig(Name, c); err != nil {
+		return err
+	}
+
+	if err := h.SetScoring(c.Scoring); err != nil {
+		return err
 	}
 
-	common.LoadConfig(Name, config)
+	loaded := loadItems(c)
 
-	if len(config.Explicits) == 0 {
+	mu.Lock()
+	config = c
+	items = loaded
+	mu.Unlock()
+
+	migrate()
+
+	return nil
+}
+
+// migrate maps history entries keyed by the identifier to the binary, so the history survives changes of $PATH.
+// Entries of binaries not found are kept and migrated once they are available again.
+func migrate() {
+	mu.RLock()
+	bins := make(map[string]string, len(items))
+
+	for _, v := range items {
+		bins[v.Identifier] = v.Bin
+	}
+	mu.RUnlock()
+
+	h.Migrate(func(identifier string) (string, bool) {
+		bin, ok := bins[identifier]
+		return bin, ok
+	})
+}
+
+// loadItems collects either the explicitly configured commands or all executables in $PATH.
+func loadItems(c *Config) []Item {
+	res := []Item{}
+
+	if len(c.Explicits) == 0 {
 		bins := []string{}
 
 		for p := range strings.SplitSeq(os.Getenv("PATH"), ":") {
@@ -92,17 +184,17 @@ func init() {
 			md5 := md5.Sum([]byte(v))
 			md5str := hex.EncodeToString(md5[:])
 
-			items = append(items, Item{
+			res = append(res, Item{
 				Identifier: md5str,
 				Bin:        v,
 			})
 		}
 	} else {
-		for _, v := range config.Explicits {
+		for _, v := range c.Explicits {
 			md5 := md5.Sum([]byte(v.Exec))
 			identifier := hex.EncodeToString(md5[:])
 
-			items = append(items, Item{
+			res = append(res, Item{
 				Identifier: identifier,
 				Bin:        v.Exec,
 				Alias:      v.Alias,
@@ -110,17 +202,17 @@ func init() {
 		}
 	}
 
-	slog.Info(Name, "executables", len(items), "time", time.Since(start))
+	return res
 }
 
-func PrintDoc() {
+func (plugin) PrintDoc() {
 	fmt.Printf("### %s\n", NamePretty)
 	fmt.Println("Run everything in your $PATH!")
 	fmt.Println()
 	util.PrintConfig(Config{}, Name)
 }
 
-func Cleanup(qid uint32) {
+func (plugin) Cleanup(qid uint32) {
 	slog.Info(Name, "cleanup", qid)
 	results.Lock()
 	delete(results.Queries, qid)
@@ -132,7 +224,17 @@ const (
 	ActionRunInTerminal = "runterminal"
 )
 
-func Activate(qid uint32, identifier, action string, arguments string) {
+// Actions returns the actions supported by Activate.
+func (plugin) Actions() []string {
+	return []string{ActionRun, ActionRunInTerminal}
+}
+
+var itemActions = []*pb.QueryResponse_Item_Action{
+	{Name: ActionRun, Label: "Run", Icon: "system-run", Default: true},
+	{Name: ActionRunInTerminal, Label: "Run in Terminal", Icon: "utilities-terminal"},
+}
+
+func (plugin) Activate(qid uint32, identifier, action string, arguments string) error {
 	bin := ""
 
 	splits := strings.Split(arguments, common.GetElephantConfig().ArgumentDelimiter)
@@ -142,16 +244,29 @@ func Activate(qid uint32, identifier, action string, arguments string) {
 		arguments = ""
 	}
 
-	for _, v := range items {
+	mu.RLock()
+	cfg, list := config, items
+	mu.RUnlock()
+
+	for _, v := range list {
 		if v.Identifier == identifier {
 			bin = v.Bin
 			break
 		}
 	}
 
+	if bin == "" {
+		return provider.ErrUnknownIdentifier
+	}
+
 	run := strings.TrimSpace(fmt.Sprintf("%s %s", bin, arguments))
-	if action == ActionRunInTerminal {
+
+	switch action {
+	case "", ActionRun:
+	case ActionRunInTerminal:
 		run = common.WrapWithTerminal(run)
+	default:
+		return fmt.Errorf("%w: %s", provider.ErrActionNotSupported, action)
 	}
 
 	cmd := exec.Command("sh", "-c", run)
@@ -162,14 +277,14 @@ func Activate(qid uint32, identifier, action string, arguments string) {
 
 	err := cmd.Start()
 	if err != nil {
-		slog.Error(Name, "activate", err)
-	} else {
-		go func() {
-			cmd.Wait()
-		}()
+		return fmt.Errorf("%w: %w", provider.ErrCommandFailed, err)
 	}
 
-	if config.History {
+	go func() {
+		cmd.Wait()
+	}()
+
+	if cfg.History {
 		var last uint32
 
 		for k := range results.Queries[qid] {
@@ -179,29 +294,36 @@ func Activate(qid uint32, identifier, action string, arguments string) {
 		}
 
 		if last != 0 {
-			h.Save(results.Queries[qid][last], identifier)
+			h.Save(results.Queries[qid][last], bin)
 		} else {
-			h.Save("", identifier)
+			h.Save("", bin)
 		}
 	}
+
+	return nil
 }
 
-func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.QueryResponse_Item {
+func (plugin) Query(_ context.Context, qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.QueryResponse_Item {
 	entries := []*pb.QueryResponse_Item{}
 
 	if query != "" {
 		results.GetData(query, qid, iid, exact)
 	}
 
-	for _, v := range items {
+	mu.RLock()
+	cfg, list := config, items
+	mu.RUnlock()
+
+	for _, v := range list {
 		e := &pb.QueryResponse_Item{
 			Identifier: v.Identifier,
 			Text:       v.Bin,
 			Provider:   Name,
-			Icon:       config.Icon,
+			Icon:       cfg.Icon,
 			Score:      0,
 			Fuzzyinfo:  &pb.QueryResponse_Item_FuzzyInfo{},
 			Type:       pb.QueryResponse_REGULAR,
+			Actions:    itemActions,
 		}
 
 		if query != "" {
@@ -224,12 +346,12 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 		}
 
 		var usageScore int32
-		if config.History && (e.Score > config.MinScore || query == "") {
-			usageScore = h.CalcUsageScore(query, e.Identifier)
+		if cfg.History && (e.Score > cfg.MinScore || query == "") {
+			usageScore = h.CalcUsageScore(query, v.Bin)
 			e.Score = e.Score + usageScore
 		}
 
-		if e.Score > config.MinScore || query == "" {
+		if e.Score > cfg.MinScore || query == "" {
 			entries = append(entries, e)
 		}
 	}
@@ -237,6 +359,9 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 	return entries
 }
 
-func Icon() string {
+func (plugin) Icon() string {
+	mu.RLock()
+	defer mu.RUnlock()
+
 	return config.Icon
 }
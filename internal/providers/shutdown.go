@@ -0,0 +1,62 @@
+package providers
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/abenz1267/elephant/internal/common/history"
+	"github.com/abenz1267/elephant/pkg/provider"
+)
+
+// shutdownTimeout is how long providers get to shut down before elephant exits anyways.
+const shutdownTimeout = 5 * time.Second
+
+// Shutdown shuts down all providers concurrently and flushes their histories.
+func Shutdown() {
+	start := time.Now()
+
+	var wg sync.WaitGroup
+
+	for _, p := range Providers {
+		s, ok := p.(provider.Shutdowner)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			shutdown(p.Name(), s)
+		}()
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(shutdownTimeout):
+		slog.Error("providers", "shutdown", "timed out", "after", shutdownTimeout)
+	}
+
+	history.Flush()
+
+	slog.Info("providers", "shutdown", len(Providers), "time", time.Since(start))
+}
+
+// shutdown runs the providers shutdown, a panicking provider won't keep the others from shutting down.
+func shutdown(name string, s provider.Shutdowner) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error(name, "shutdown", r)
+		}
+	}()
+
+	s.Shutdown()
+}
@@ -1,4 +1,4 @@
-package main
+package symbols
 
 import (
 	"crypto/md5"
@@ -7,7 +7,6 @@ import (
 	"encoding/hex"
 	"encoding/xml"
 	"fmt"
-	"log/slog"
 	"strings"
 )
 
@@ -50,18 +49,20 @@ type Symbol struct {
 
 var symbols = make(map[string]*Symbol)
 
-func parse() {
-	file, err := files.ReadFile(fmt.Sprintf("data/%s.xml", config.Locale))
+// parse returns the symbols of the locale.
+func parse(locale string) (map[string]*Symbol, error) {
+	symbols := make(map[string]*Symbol)
+
+	file, err := files.ReadFile(fmt.Sprintf("data/%s.xml", locale))
 	if err != nil {
-		slog.Error(Name, "parsing", err)
-		return
+		return nil, fmt.Errorf("unknown locale '%s'", locale)
 	}
 
 	var ldml LDML
 
 	err = xml.Unmarshal(file, &ldml)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	for _, v := range ldml.Annotations.Annotation {
@@ -95,4 +96,6 @@ func parse() {
 			v.Searchable[n] = strings.TrimSpace(m)
 		}
 	}
+
+	return symbols, nil
 }
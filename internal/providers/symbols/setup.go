@@ -1,13 +1,15 @@
 // Package symbols provides symbols/emojis.
-package main
+package symbols
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"log/slog"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/abenz1267/elephant/internal/common"
@@ -15,43 +17,137 @@ import (
 	"github.com/abenz1267/elephant/internal/providers"
 	"github.com/abenz1267/elephant/internal/util"
 	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"github.com/abenz1267/elephant/pkg/provider"
 )
 
 var (
 	Name       = "symbols"
 	NamePretty = "Symbols/Emojis"
-	h          = history.Load(Name)
+	h          *history.History
 	results    = providers.QueryData{}
 )
 
+type plugin struct{}
+
+// Provider is looked up by elephant when loading the plugin.
+var Provider provider.Provider = plugin{}
+
+func (plugin) APIVersion() int {
+	return provider.APIVersion
+}
+
+func (plugin) Name() string {
+	return Name
+}
+
+func (plugin) NamePretty() string {
+	return NamePretty
+}
+
 type Config struct {
 	common.Config `koanf:",squash"`
-	Locale        string `koanf:"locale" desc:"locale to use for symbols" default:"en"`
-	History       bool   `koanf:"history" desc:"make use of history for sorting" default:"false"`
+	Locale        string          `koanf:"locale" desc:"locale to use for symbols" default:"en"`
+	History       bool            `koanf:"history" desc:"make use of history for sorting" default:"false"`
+	Scoring       history.Scoring `koanf:"scoring" desc:"how history is used for sorting" default:""`
+}
+
+var config atomic.Pointer[Config]
+
+const ActionCopy = "copy"
+
+// Actions returns the actions supported by Activate.
+func (plugin) Actions() []string {
+	return []string{ActionCopy}
 }
 
-var config *Config
+var itemActions = []*pb.QueryResponse_Item_Action{
+	{Name: ActionCopy, Label: "Copy", Icon: "edit-copy", Default: true},
+}
 
-func init() {
+func (plugin) Setup() error {
 	start := time.Now()
+	h = history.Load(Name)
+
+	c := defaultConfig()
+
+	if err := common.ReadConfig(Name, c); err != nil {
+		return err
+	}
+
+	config.Store(c)
+
+	if err := h.SetScoring(c.Scoring); err != nil {
+		slog.Error(Name, "scoring", err)
+	}
+
+	var err error
+
+	symbols, err = parse(c.Locale)
+	if err != nil {
+		return err
+	}
+
+	// history used to be keyed by the identifier, it's the codepoint now
+	h.Migrate(func(identifier string) (string, bool) {
+		if s, ok := symbols[identifier]; ok {
+			return s.CP, true
+		}
+
+		return "", false
+	})
 
-	config = &Config{
+	slog.Info(Name, "symbols/emojis", len(symbols), "time", time.Since(start))
+
+	return nil
+}
+
+func (plugin) DefaultConfig() any {
+	return defaultConfig()
+}
+
+func defaultConfig() *Config {
+	return &Config{
 		Config: common.Config{
 			Icon:     "face-smile",
 			MinScore: 50,
 		},
 		Locale:  "en",
 		History: false,
+		Scoring: history.DefaultScoring(),
 	}
+}
 
-	common.LoadConfig(Name, config)
+func (plugin) Reload() error {
+	c := defaultConfig()
 
-	parse()
+	if err := common.ReadConfig(Name, c); err != nil {
+		return err
+	}
 
-	slog.Info(Name, "symbols/emojis", len(symbols), "time", time.Since(start))
+	var parsed map[string]*Symbol
+
+	if c.Locale != config.Load().Locale {
+		var err error
+
+		if parsed, err = parse(c.Locale); err != nil {
+			return err
+		}
+	}
+
+	if err := h.SetScoring(c.Scoring); err != nil {
+		return err
+	}
+
+	if parsed != nil {
+		symbols = parsed
+	}
+
+	config.Store(c)
+
+	return nil
 }
 
-func PrintDoc() {
+func (plugin) PrintDoc() {
 	fmt.Printf("### %s\n", NamePretty)
 	fmt.Println("Find symbols and emojis.")
 	fmt.Println()
@@ -71,27 +167,36 @@ func PrintDoc() {
 	util.PrintConfig(Config{}, Name)
 }
 
-func Cleanup(qid uint32) {
+func (plugin) Cleanup(qid uint32) {
 	slog.Info(Name, "cleanup", qid)
 	results.Lock()
 	delete(results.Queries, qid)
 	results.Unlock()
 }
 
-func Activate(qid uint32, identifier, action string, arguments string) {
+func (plugin) Activate(qid uint32, identifier, action string, arguments string) error {
+	symbol, ok := symbols[identifier]
+	if !ok {
+		return provider.ErrUnknownIdentifier
+	}
+
+	if action != "" && action != ActionCopy {
+		return fmt.Errorf("%w: %s", provider.ErrActionNotSupported, action)
+	}
+
 	cmd := exec.Command("wl-copy")
-	cmd.Stdin = strings.NewReader(symbols[identifier].CP)
+	cmd.Stdin = strings.NewReader(symbol.CP)
 
 	err := cmd.Start()
 	if err != nil {
-		slog.Error(Name, "activate", err)
-	} else {
-		go func() {
-			cmd.Wait()
-		}()
+		return fmt.Errorf("%w: %w", provider.ErrCommandFailed, err)
 	}
 
-	if config.History {
+	go func() {
+		cmd.Wait()
+	}()
+
+	if config.Load().History {
 		var last uint32
 
 		for k := range results.Queries[qid] {
@@ -101,14 +206,18 @@ func Activate(qid uint32, identifier, action string, arguments string) {
 		}
 
 		if last != 0 {
-			h.Save(results.Queries[qid][last], identifier)
+			h.Save(results.Queries[qid][last], symbol.CP)
 		} else {
-			h.Save("", identifier)
+			h.Save("", symbol.CP)
 		}
 	}
+
+	return nil
 }
 
-func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.QueryResponse_Item {
+func (plugin) Query(_ context.Context, qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.QueryResponse_Item {
+	cfg := config.Load()
+
 	start := time.Now()
 	entries := []*pb.QueryResponse_Item{}
 
@@ -149,12 +258,12 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 		}
 
 		var usageScore int32
-		if config.History && (score > config.MinScore || query == "") {
-			usageScore = h.CalcUsageScore(query, k)
+		if cfg.History && (score > cfg.MinScore || query == "") {
+			usageScore = h.CalcUsageScore(query, v.CP)
 			score = score + usageScore
 		}
 
-		if usageScore != 0 || score > config.MinScore || query == "" {
+		if usageScore != 0 || score > cfg.MinScore || query == "" {
 			entries = append(entries, &pb.QueryResponse_Item{
 				Identifier: k,
 				Score:      score,
@@ -166,7 +275,8 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 					Field:     field,
 					Positions: positions,
 				},
-				Type: pb.QueryResponse_REGULAR,
+				Type:    pb.QueryResponse_REGULAR,
+				Actions: itemActions,
 			})
 		}
 	}
@@ -175,6 +285,6 @@ func Query(qid uint32, iid uint32, query string, _ bool, exact bool) []*pb.Query
 	return entries
 }
 
-func Icon() string {
-	return config.Icon
+func (plugin) Icon() string {
+	return config.Load().Icon
 }
@@ -0,0 +1,73 @@
+package symbols
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abenz1267/elephant/internal/common"
+)
+
+// writeConfig uses a config dir only containing the given config.
+func writeConfig(t *testing.T, cfg string) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, Name+".toml"), []byte(cfg), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	common.SetExplicitDir(dir)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Cleanup(func() { common.SetExplicitDir("") })
+}
+
+func TestParseLocales(t *testing.T) {
+	entries, err := files.ReadDir("data")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range entries {
+		locale := strings.TrimSuffix(v.Name(), ".xml")
+
+		if _, err := parse(locale); err != nil {
+			t.Errorf("%s: %v", locale, err)
+		}
+	}
+}
+
+func TestParseUnknownLocale(t *testing.T) {
+	if _, err := parse("unknown"); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestQuery(t *testing.T) {
+	writeConfig(t, `locale = "en"`)
+
+	if err := (plugin{}).Setup(); err != nil {
+		t.Fatal(err)
+	}
+
+	res := (plugin{}).Query(context.Background(), 1, 1, "grinning face", false, true)
+
+	for _, v := range res {
+		if v.Icon == "😀" {
+			return
+		}
+	}
+
+	t.Errorf("😀 not found in %d results", len(res))
+}
+
+func TestSetupInvalidLocale(t *testing.T) {
+	writeConfig(t, `locale = "unknown"`)
+
+	if err := (plugin{}).Setup(); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,62 @@
+package providers
+
+import (
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"slices"
+
+	"github.com/abenz1267/elephant/internal/common"
+	"github.com/abenz1267/elephant/pkg/provider"
+)
+
+// Validate checks elephant.toml, the menus and the configs of all installed providers.
+func Validate() []common.Diagnostic {
+	diags := common.ValidateConfig("elephant", &common.ElephantConfig{})
+	diags = append(diags, common.ValidateMenus()...)
+
+	for _, p := range Discover() {
+		if c, ok := p.(provider.Configurable); ok {
+			diags = append(diags, common.ValidateConfig(p.Name(), c.DefaultConfig())...)
+		}
+	}
+
+	return diags
+}
+
+// Discover returns the built-in providers and all plugins without setting them up. External providers aren't started.
+func Discover() []provider.Provider {
+	res := slices.Clone(builtin)
+	have := []string{}
+
+	for _, p := range builtin {
+		have = append(have, p.Name()+".so")
+	}
+
+	dirs := []string{filepath.Join(common.ConfigDir(), "providers"), "/etc/xdg/elephant/providers"}
+
+	for _, root := range dirs {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || filepath.Ext(path) != ".so" || slices.Contains(have, filepath.Base(path)) {
+				return nil
+			}
+
+			p, err := open(path)
+			if err != nil {
+				slog.Error("providers", "skipping", path, "reason", err)
+				return nil
+			}
+
+			if slices.ContainsFunc(res, func(v provider.Provider) bool { return v.Name() == p.Name() }) {
+				return nil
+			}
+
+			res = append(res, p)
+			have = append(have, filepath.Base(path))
+
+			return nil
+		})
+	}
+
+	return res
+}
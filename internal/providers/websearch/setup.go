@@ -1,32 +1,63 @@
-package main
+// Package websearch provides user defined search engines.
+package websearch
 
 import (
+	"context"
 	"fmt"
-	"log/slog"
 	"net/url"
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 
 	"github.com/abenz1267/elephant/internal/comm/handlers"
 	"github.com/abenz1267/elephant/internal/common"
 	"github.com/abenz1267/elephant/internal/util"
 	"github.com/abenz1267/elephant/pkg/pb/pb"
+	"github.com/abenz1267/elephant/pkg/provider"
 )
 
 var (
 	Name       = "websearch"
 	NamePretty = "Websearch"
-	config     *Config
+	config     atomic.Pointer[Config]
 )
 
+type plugin struct{}
+
+// Provider is looked up by elephant when loading the plugin.
+var Provider provider.Provider = plugin{}
+
+func (plugin) APIVersion() int {
+	return provider.APIVersion
+}
+
+func (plugin) Name() string {
+	return Name
+}
+
+func (plugin) NamePretty() string {
+	return NamePretty
+}
+
 const (
 	ActionCopy   = "copy"
 	ActionSave   = "save"
 	ActionDelete = "delete"
 )
 
+const ActionSearch = "search"
+
+// Actions returns the actions supported by Activate.
+func (plugin) Actions() []string {
+	return []string{ActionSearch}
+}
+
+var itemActions = []*pb.QueryResponse_Item_Action{
+	{Name: ActionSearch, Label: "Search", Icon: "system-search", Default: true, RequiresArgument: true},
+}
+
 type Config struct {
 	common.Config           `koanf:",squash"`
 	Entries                 []Entry `koanf:"entries" desc:"entries" default:""`
@@ -41,48 +72,95 @@ type Entry struct {
 	Icon    string `koanf:"icon" desc:"icon to display, fallsback to global" default:""`
 }
 
-var prefixes = make(map[string]int)
+func (plugin) Setup() error {
+	c := defaultConfig()
 
-func init() {
-	config = &Config{
-		Config: common.Config{
-			Icon: "applications-internet",
-		},
-		MaxGlobalItemsToDisplay: 1,
+	if err := common.ReadConfig(Name, c); err != nil {
+		return err
 	}
 
-	common.LoadConfig(Name, config)
-	handlers.MaxGlobalItemsToDisplayWebsearch = config.MaxGlobalItemsToDisplay
+	config.Store(c)
+
+	applyConfig()
+
+	return nil
+}
+
+func (plugin) Reload() error {
+	c := defaultConfig()
+
+	if err := common.ReadConfig(Name, c); err != nil {
+		return err
+	}
+
+	config.Store(c)
+	applyConfig()
+
+	return nil
+}
+
+func applyConfig() {
+	cfg := config.Load()
+	prefixes := make(map[string]string)
 
-	for k, v := range config.Entries {
+	for _, v := range cfg.Entries {
 		if v.Prefix != "" {
-			prefixes[v.Prefix] = k
-			handlers.WebsearchPrefixes[v.Prefix] = v.Name
+			prefixes[v.Prefix] = v.Name
 		}
 	}
+
+	handlers.SetWebsearch(prefixes, cfg.MaxGlobalItemsToDisplay)
+}
+
+// prefix returns the prefix of the entry the query starts with, if any.
+func (c *Config) prefix(query string) string {
+	for _, v := range c.Entries {
+		if v.Prefix != "" && strings.HasPrefix(query, v.Prefix) {
+			return v.Prefix
+		}
+	}
+
+	return ""
+}
+
+func (plugin) DefaultConfig() any {
+	return defaultConfig()
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Config: common.Config{
+			Icon: "applications-internet",
+		},
+		MaxGlobalItemsToDisplay: 1,
+	}
 }
 
-func PrintDoc() {
+func (plugin) PrintDoc() {
 	fmt.Printf("### %s\n", NamePretty)
 	fmt.Println("Websearch: search the web with custom defined searches")
 	fmt.Println()
 	util.PrintConfig(Config{}, Name)
 }
 
-func Cleanup(qid uint32) {
+func (plugin) Cleanup(qid uint32) {
 }
 
-func Activate(qid uint32, identifier, action string, query string) {
-	i, _ := strconv.Atoi(identifier)
+func (plugin) Activate(qid uint32, identifier, action string, query string) error {
+	cfg := config.Load()
 
-	for k := range prefixes {
-		if after, ok := strings.CutPrefix(query, k); ok {
-			query = after
-			break
-		}
+	i, err := strconv.Atoi(identifier)
+	if err != nil || i < 0 || i >= len(cfg.Entries) {
+		return provider.ErrUnknownIdentifier
 	}
 
-	url := strings.ReplaceAll(config.Entries[i].URL, "%TERM%", url.QueryEscape(query))
+	if action != "" && action != ActionSearch {
+		return fmt.Errorf("%w: %s", provider.ErrActionNotSupported, action)
+	}
+
+	query = strings.TrimPrefix(query, cfg.prefix(query))
+
+	url := strings.ReplaceAll(cfg.Entries[i].URL, "%TERM%", url.QueryEscape(query))
 
 	prefix := common.LaunchPrefix("")
 
@@ -92,33 +170,30 @@ func Activate(qid uint32, identifier, action string, query string) {
 		Setsid: true,
 	}
 
-	err := cmd.Start()
+	err = cmd.Start()
 	if err != nil {
-		slog.Error(Name, "activate", err)
-	} else {
-		go func() {
-			cmd.Wait()
-		}()
+		return fmt.Errorf("%w: %w", provider.ErrCommandFailed, err)
 	}
+
+	go func() {
+		cmd.Wait()
+	}()
+
+	return nil
 }
 
-func Query(qid uint32, iid uint32, query string, single bool, _ bool) []*pb.QueryResponse_Item {
-	entries := []*pb.QueryResponse_Item{}
+func (plugin) Query(_ context.Context, qid uint32, iid uint32, query string, single bool, _ bool) []*pb.QueryResponse_Item {
+	cfg := config.Load()
 
-	prefix := ""
+	entries := []*pb.QueryResponse_Item{}
 
-	for k := range prefixes {
-		if strings.HasPrefix(query, k) {
-			prefix = k
-			break
-		}
-	}
+	prefix := cfg.prefix(query)
 
 	if single {
-		for k, v := range config.Entries {
+		for k, v := range cfg.Entries {
 			icon := v.Icon
 			if icon == "" {
-				icon = config.Icon
+				icon = cfg.Icon
 			}
 
 			e := &pb.QueryResponse_Item{
@@ -129,16 +204,17 @@ func Query(qid uint32, iid uint32, query string, single bool, _ bool) []*pb.Quer
 				Provider:   Name,
 				Score:      int32(100 - k),
 				Type:       0,
+				Actions:    itemActions,
 			}
 
 			entries = append(entries, e)
 		}
 	} else {
-		for k, v := range config.Entries {
+		for k, v := range cfg.Entries {
 			if v.Default || v.Prefix == prefix {
 				icon := v.Icon
 				if icon == "" {
-					icon = config.Icon
+					icon = cfg.Icon
 				}
 
 				e := &pb.QueryResponse_Item{
@@ -149,6 +225,7 @@ func Query(qid uint32, iid uint32, query string, single bool, _ bool) []*pb.Quer
 					Provider:   Name,
 					Score:      int32(100 - k),
 					Type:       0,
+					Actions:    itemActions,
 				}
 
 				entries = append(entries, e)
@@ -159,6 +236,6 @@ func Query(qid uint32, iid uint32, query string, single bool, _ bool) []*pb.Quer
 	return entries
 }
 
-func Icon() string {
-	return config.Icon
+func (plugin) Icon() string {
+	return config.Load().Icon
 }
@@ -0,0 +1,121 @@
+package websearch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/abenz1267/elephant/internal/common"
+	"github.com/abenz1267/elephant/pkg/provider"
+)
+
+const testConfig = `
+[[entries]]
+name = "Google"
+default = true
+url = "https://www.google.com/search?q=%TERM%"
+
+[[entries]]
+name = "Wikipedia"
+prefix = "w:"
+url = "https://en.wikipedia.org/wiki/%TERM%"
+`
+
+// setupTest runs the setup with the given config.
+func setupTest(t *testing.T, cfg string) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, Name+".toml"), []byte(cfg), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	common.SetExplicitDir(dir)
+	t.Cleanup(func() { common.SetExplicitDir("") })
+
+	if err := (plugin{}).Setup(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func queryTexts(query string, single bool) []string {
+	res := []string{}
+
+	for _, v := range (plugin{}).Query(context.Background(), 1, 1, query, single, false) {
+		res = append(res, v.Text)
+	}
+
+	return res
+}
+
+func TestQuery(t *testing.T) {
+	setupTest(t, testConfig)
+
+	tests := []struct {
+		query  string
+		single bool
+		want   []string
+	}{
+		{"elephant", false, []string{"Google"}},
+		{"w:elephant", false, []string{"Google", "Wikipedia"}},
+		{"elephant", true, []string{"Google", "Wikipedia"}},
+	}
+
+	for _, tt := range tests {
+		if got := queryTexts(tt.query, tt.single); !slices.Equal(got, tt.want) {
+			t.Errorf("%q single=%t: got %v, want %v", tt.query, tt.single, got, tt.want)
+		}
+	}
+}
+
+func TestActivateErrors(t *testing.T) {
+	setupTest(t, testConfig)
+
+	if err := (plugin{}).Activate(1, "2", "", "elephant"); !errors.Is(err, provider.ErrUnknownIdentifier) {
+		t.Errorf("unknown identifier: got %v", err)
+	}
+
+	if err := (plugin{}).Activate(1, "0", ActionCopy, "elephant"); !errors.Is(err, provider.ErrActionNotSupported) {
+		t.Errorf("unsupported action: got %v", err)
+	}
+}
+
+func TestReloadKeepsConfigOnError(t *testing.T) {
+	setupTest(t, testConfig)
+
+	if err := os.WriteFile(common.ProviderConfig(Name), []byte("[[entries]"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := (plugin{}).Reload(); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if got := queryTexts("w:elephant", false); len(got) != 2 {
+		t.Errorf("got %v, want the previous entries", got)
+	}
+}
+
+func TestReloadWhileQuerying(t *testing.T) {
+	setupTest(t, testConfig)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for range 100 {
+			(plugin{}).Reload()
+		}
+	}()
+
+	for range 100 {
+		queryTexts("w:elephant", false)
+	}
+
+	<-done
+}
@@ -0,0 +1,109 @@
+// Package systemd provides socket activation, readiness notifications and unit files for running elephant as a systemd user service.
+package systemd
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd.
+const listenFDsStart = 3
+
+// Listener returns the socket passed via socket activation, nil if elephant wasn't socket activated.
+func Listener() (*net.UnixListener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n < 1 {
+		return nil, nil
+	}
+
+	// don't pass the sockets on to spawned processes
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	if n > 1 {
+		slog.Warn("systemd", "listen_fds", n, "using", "first")
+	}
+
+	for fd := listenFDsStart; fd < listenFDsStart+n; fd++ {
+		unix.CloseOnExec(fd)
+	}
+
+	f := os.NewFile(uintptr(listenFDsStart), "LISTEN_FD_3")
+	defer f.Close()
+
+	l, err := net.FileListener(f)
+	if err != nil {
+		return nil, err
+	}
+
+	ul, ok := l.(*net.UnixListener)
+	if !ok {
+		l.Close()
+		return nil, fmt.Errorf("passed socket is %s, expected unix", l.Addr().Network())
+	}
+
+	// the socket is owned by systemd
+	ul.SetUnlinkOnClose(false)
+
+	return ul, nil
+}
+
+// Notify sends the state to the service manager, f.e. "READY=1". It's a no-op if not running as a notify service.
+func Notify(state ...string) {
+	addr := os.Getenv("NOTIFY_SOCKET")
+	if addr == "" {
+		return
+	}
+
+	// abstract sockets
+	if strings.HasPrefix(addr, "@") {
+		addr = "\x00" + addr[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		slog.Error("systemd", "notify", err)
+		return
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(strings.Join(state, "\n"))); err != nil {
+		slog.Error("systemd", "notify", err)
+	}
+}
+
+// Ready signals that elephant finished starting up.
+func Ready() {
+	Notify("READY=1")
+}
+
+// Reloading signals that elephant started reloading, Ready has to be sent once finished.
+func Reloading() {
+	var ts unix.Timespec
+
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &ts); err != nil {
+		slog.Error("systemd", "monotonic", err)
+		Notify("RELOADING=1")
+
+		return
+	}
+
+	Notify("RELOADING=1", fmt.Sprintf("MONOTONIC_USEC=%d", ts.Nano()/1000))
+}
+
+// Stopping signals that elephant is shutting down.
+func Stopping() {
+	Notify("STOPPING=1")
+}
@@ -0,0 +1,97 @@
+package systemd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/adrg/xdg"
+)
+
+const socketUnit = `[Unit]
+Description=Elephant socket
+PartOf=graphical-session.target
+
+[Socket]
+ListenStream=%s
+SocketMode=0600
+DirectoryMode=0700
+
+[Install]
+WantedBy=sockets.target
+`
+
+const serviceUnit = `[Unit]
+Description=Elephant data provider and executor
+Requires=elephant.socket
+After=elephant.socket graphical-session.target
+PartOf=graphical-session.target
+
+[Service]
+Type=notify
+ExecStart=%s
+ExecReload=%s reload
+Restart=on-failure
+
+[Install]
+WantedBy=graphical-session.target
+`
+
+// Units returns the contents of elephant.socket and elephant.service, starting the executable with args on the
+// first connection to the socket.
+func Units(executable string, args []string, socket string) (string, string) {
+	cmd := append([]string{executable}, args...)
+
+	for k, v := range cmd {
+		if strings.ContainsAny(v, " \t\"'\\") {
+			cmd[k] = fmt.Sprintf("%q", v)
+		}
+	}
+
+	start := strings.Join(cmd, " ")
+
+	return fmt.Sprintf(socketUnit, listenStream(socket)), fmt.Sprintf(serviceUnit, start, start)
+}
+
+// listenStream returns the socket path relative to the %t specifier if it's in the runtime dir, so the unit doesn't
+// depend on the runtime dir elephant was started with.
+func listenStream(socket string) string {
+	socket = strings.ReplaceAll(socket, "%", "%%")
+
+	rel, err := filepath.Rel(xdg.RuntimeDir, socket)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return socket
+	}
+
+	return "%t/" + rel
+}
+
+// Install writes the units to the systemd user unit directory and returns their paths.
+func Install(socket, service string) ([]string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+
+		dir = filepath.Join(home, ".config")
+	}
+
+	dir = filepath.Join(dir, "systemd", "user")
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	files := []string{filepath.Join(dir, "elephant.socket"), filepath.Join(dir, "elephant.service")}
+
+	for k, v := range []string{socket, service} {
+		if err := os.WriteFile(files[k], []byte(v), 0o644); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,38 @@
+package systemd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrg/xdg"
+)
+
+func TestListenStream(t *testing.T) {
+	tests := []struct {
+		socket string
+		want   string
+	}{
+		{filepath.Join(xdg.RuntimeDir, "elephant", "elephant.sock"), "%t/elephant/elephant.sock"},
+		{"/tmp/elephant.sock", "/tmp/elephant.sock"},
+		{"/tmp/100%.sock", "/tmp/100%%.sock"},
+	}
+
+	for _, tt := range tests {
+		if got := listenStream(tt.socket); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.socket, got, tt.want)
+		}
+	}
+}
+
+func TestUnits(t *testing.T) {
+	socket, service := Units("/usr/bin/elephant", []string{"--config", "/my dir"}, filepath.Join(xdg.RuntimeDir, "elephant", "elephant.sock"))
+
+	if !strings.Contains(socket, "ListenStream=%t/elephant/elephant.sock\n") {
+		t.Errorf("socket unit:\n%s", socket)
+	}
+
+	if !strings.Contains(service, `ExecStart=/usr/bin/elephant --config "/my dir"`+"\n") {
+		t.Errorf("service unit:\n%s", service)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/abenz1267/elephant/internal/providers"
+	"github.com/abenz1267/elephant/pkg/provider"
 )
 
 func GenerateDoc() {
@@ -19,14 +20,14 @@ func GenerateDoc() {
 
 	fmt.Println("## Provider Configuration")
 
-	p := []providers.Provider{}
+	p := []provider.Provider{}
 
 	for _, v := range providers.Providers {
 		p = append(p, v)
 	}
 
-	slices.SortFunc(p, func(a, b providers.Provider) int {
-		return strings.Compare(*a.NamePretty, *b.NamePretty)
+	slices.SortFunc(p, func(a, b provider.Provider) int {
+		return strings.Compare(a.NamePretty(), b.NamePretty())
 	})
 
 	for _, v := range p {
@@ -91,6 +92,10 @@ func printStructDesc(c any) {
 					nestedStructs = append(nestedStructs, elemType)
 				}
 			}
+
+			if field.Type.Kind() == reflect.Struct && field.Type.PkgPath() != "time" {
+				nestedStructs = append(nestedStructs, field.Type)
+			}
 		}
 	}
 
@@ -97,6 +97,74 @@ func (x *ActivateRequest) GetArguments() string {
 	return ""
 }
 
+type ActivateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Qid           int32                  `protobuf:"varint,1,opt,name=qid,proto3" json:"qid,omitempty"`
+	Provider      string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Identifier    string                 `protobuf:"bytes,3,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActivateResponse) Reset() {
+	*x = ActivateResponse{}
+	mi := &file_activate_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActivateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActivateResponse) ProtoMessage() {}
+
+func (x *ActivateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_activate_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActivateResponse.ProtoReflect.Descriptor instead.
+func (*ActivateResponse) Descriptor() ([]byte, []int) {
+	return file_activate_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ActivateResponse) GetQid() int32 {
+	if x != nil {
+		return x.Qid
+	}
+	return 0
+}
+
+func (x *ActivateResponse) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ActivateResponse) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *ActivateResponse) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
 var File_activate_proto protoreflect.FileDescriptor
 
 const file_activate_proto_rawDesc = "" +
@@ -109,7 +177,14 @@ const file_activate_proto_rawDesc = "" +
 	"identifier\x18\x03 \x01(\tR\n" +
 	"identifier\x12\x16\n" +
 	"\x06action\x18\x04 \x01(\tR\x06action\x12\x1c\n" +
-	"\targuments\x18\x05 \x01(\tR\targumentsB\x06Z\x04./pbb\x06proto3"
+	"\targuments\x18\x05 \x01(\tR\targuments\"x\n" +
+	"\x10ActivateResponse\x12\x10\n" +
+	"\x03qid\x18\x01 \x01(\x05R\x03qid\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x03 \x01(\tR\n" +
+	"identifier\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06actionB\x06Z\x04./pbb\x06proto3"
 
 var (
 	file_activate_proto_rawDescOnce sync.Once
@@ -123,9 +198,10 @@ func file_activate_proto_rawDescGZIP() []byte {
 	return file_activate_proto_rawDescData
 }
 
-var file_activate_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_activate_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_activate_proto_goTypes = []any{
-	(*ActivateRequest)(nil), // 0: pb.ActivateRequest
+	(*ActivateRequest)(nil),  // 0: pb.ActivateRequest
+	(*ActivateResponse)(nil), // 1: pb.ActivateResponse
 }
 var file_activate_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -146,7 +222,7 @@ func file_activate_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_activate_proto_rawDesc), len(file_activate_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
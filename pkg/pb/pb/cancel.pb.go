@@ -0,0 +1,113 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v6.31.1
+// source: cancel.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CancelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRequest) Reset() {
+	*x = CancelRequest{}
+	mi := &file_cancel_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRequest) ProtoMessage() {}
+
+func (x *CancelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cancel_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRequest.ProtoReflect.Descriptor instead.
+func (*CancelRequest) Descriptor() ([]byte, []int) {
+	return file_cancel_proto_rawDescGZIP(), []int{0}
+}
+
+var File_cancel_proto protoreflect.FileDescriptor
+
+const file_cancel_proto_rawDesc = "" +
+	"\n" +
+	"\fcancel.proto\x12\x02pb\"\x0f\n" +
+	"\rCancelRequestB\x06Z\x04./pbb\x06proto3"
+
+var (
+	file_cancel_proto_rawDescOnce sync.Once
+	file_cancel_proto_rawDescData []byte
+)
+
+func file_cancel_proto_rawDescGZIP() []byte {
+	file_cancel_proto_rawDescOnce.Do(func() {
+		file_cancel_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_cancel_proto_rawDesc), len(file_cancel_proto_rawDesc)))
+	})
+	return file_cancel_proto_rawDescData
+}
+
+var file_cancel_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_cancel_proto_goTypes = []any{
+	(*CancelRequest)(nil), // 0: pb.CancelRequest
+}
+var file_cancel_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_cancel_proto_init() }
+func file_cancel_proto_init() {
+	if File_cancel_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cancel_proto_rawDesc), len(file_cancel_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_cancel_proto_goTypes,
+		DependencyIndexes: file_cancel_proto_depIdxs,
+		MessageInfos:      file_cancel_proto_msgTypes,
+	}.Build()
+	File_cancel_proto = out.File
+	file_cancel_proto_goTypes = nil
+	file_cancel_proto_depIdxs = nil
+}
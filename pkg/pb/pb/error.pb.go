@@ -0,0 +1,208 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v6.31.1
+// source: error.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ErrorResponse_Code int32
+
+const (
+	ErrorResponse_UNKNOWN               ErrorResponse_Code = 0
+	ErrorResponse_INVALID_REQUEST       ErrorResponse_Code = 1
+	ErrorResponse_UNKNOWN_REQUEST_TYPE  ErrorResponse_Code = 2
+	ErrorResponse_UNKNOWN_PROVIDER      ErrorResponse_Code = 3
+	ErrorResponse_UNKNOWN_IDENTIFIER    ErrorResponse_Code = 4
+	ErrorResponse_ACTION_NOT_SUPPORTED  ErrorResponse_Code = 5
+	ErrorResponse_COMMAND_FAILED        ErrorResponse_Code = 6
+	ErrorResponse_INCOMPATIBLE_PROTOCOL ErrorResponse_Code = 7
+)
+
+// Enum value maps for ErrorResponse_Code.
+var (
+	ErrorResponse_Code_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "INVALID_REQUEST",
+		2: "UNKNOWN_REQUEST_TYPE",
+		3: "UNKNOWN_PROVIDER",
+		4: "UNKNOWN_IDENTIFIER",
+		5: "ACTION_NOT_SUPPORTED",
+		6: "COMMAND_FAILED",
+		7: "INCOMPATIBLE_PROTOCOL",
+	}
+	ErrorResponse_Code_value = map[string]int32{
+		"UNKNOWN":               0,
+		"INVALID_REQUEST":       1,
+		"UNKNOWN_REQUEST_TYPE":  2,
+		"UNKNOWN_PROVIDER":      3,
+		"UNKNOWN_IDENTIFIER":    4,
+		"ACTION_NOT_SUPPORTED":  5,
+		"COMMAND_FAILED":        6,
+		"INCOMPATIBLE_PROTOCOL": 7,
+	}
+)
+
+func (x ErrorResponse_Code) Enum() *ErrorResponse_Code {
+	p := new(ErrorResponse_Code)
+	*p = x
+	return p
+}
+
+func (x ErrorResponse_Code) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorResponse_Code) Descriptor() protoreflect.EnumDescriptor {
+	return file_error_proto_enumTypes[0].Descriptor()
+}
+
+func (ErrorResponse_Code) Type() protoreflect.EnumType {
+	return &file_error_proto_enumTypes[0]
+}
+
+func (x ErrorResponse_Code) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorResponse_Code.Descriptor instead.
+func (ErrorResponse_Code) EnumDescriptor() ([]byte, []int) {
+	return file_error_proto_rawDescGZIP(), []int{0, 0}
+}
+
+type ErrorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ErrorResponse_Code     `protobuf:"varint,1,opt,name=code,proto3,enum=pb.ErrorResponse_Code" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorResponse) Reset() {
+	*x = ErrorResponse{}
+	mi := &file_error_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorResponse) ProtoMessage() {}
+
+func (x *ErrorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_error_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorResponse.ProtoReflect.Descriptor instead.
+func (*ErrorResponse) Descriptor() ([]byte, []int) {
+	return file_error_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ErrorResponse) GetCode() ErrorResponse_Code {
+	if x != nil {
+		return x.Code
+	}
+	return ErrorResponse_UNKNOWN
+}
+
+func (x *ErrorResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_error_proto protoreflect.FileDescriptor
+
+const file_error_proto_rawDesc = "" +
+	"\n" +
+	"\verror.proto\x12\x02pb\"\x91\x02\n" +
+	"\rErrorResponse\x12*\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x16.pb.ErrorResponse.CodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xb9\x01\n" +
+	"\x04Code\x12\v\n" +
+	"\aUNKNOWN\x10\x00\x12\x13\n" +
+	"\x0fINVALID_REQUEST\x10\x01\x12\x18\n" +
+	"\x14UNKNOWN_REQUEST_TYPE\x10\x02\x12\x14\n" +
+	"\x10UNKNOWN_PROVIDER\x10\x03\x12\x16\n" +
+	"\x12UNKNOWN_IDENTIFIER\x10\x04\x12\x18\n" +
+	"\x14ACTION_NOT_SUPPORTED\x10\x05\x12\x12\n" +
+	"\x0eCOMMAND_FAILED\x10\x06\x12\x19\n" +
+	"\x15INCOMPATIBLE_PROTOCOL\x10\aB\x06Z\x04./pbb\x06proto3"
+
+var (
+	file_error_proto_rawDescOnce sync.Once
+	file_error_proto_rawDescData []byte
+)
+
+func file_error_proto_rawDescGZIP() []byte {
+	file_error_proto_rawDescOnce.Do(func() {
+		file_error_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_error_proto_rawDesc), len(file_error_proto_rawDesc)))
+	})
+	return file_error_proto_rawDescData
+}
+
+var file_error_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_error_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_error_proto_goTypes = []any{
+	(ErrorResponse_Code)(0), // 0: pb.ErrorResponse.Code
+	(*ErrorResponse)(nil),   // 1: pb.ErrorResponse
+}
+var file_error_proto_depIdxs = []int32{
+	0, // 0: pb.ErrorResponse.code:type_name -> pb.ErrorResponse.Code
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_error_proto_init() }
+func file_error_proto_init() {
+	if File_error_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_error_proto_rawDesc), len(file_error_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_error_proto_goTypes,
+		DependencyIndexes: file_error_proto_depIdxs,
+		EnumInfos:         file_error_proto_enumTypes,
+		MessageInfos:      file_error_proto_msgTypes,
+	}.Build()
+	File_error_proto = out.File
+	file_error_proto_goTypes = nil
+	file_error_proto_depIdxs = nil
+}
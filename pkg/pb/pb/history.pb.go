@@ -0,0 +1,406 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v6.31.1
+// source: history.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type HistoryRequest_Command int32
+
+const (
+	HistoryRequest_LIST   HistoryRequest_Command = 0
+	HistoryRequest_EXPORT HistoryRequest_Command = 1
+	HistoryRequest_IMPORT HistoryRequest_Command = 2
+	HistoryRequest_FORGET HistoryRequest_Command = 3
+	HistoryRequest_PRUNE  HistoryRequest_Command = 4
+)
+
+// Enum value maps for HistoryRequest_Command.
+var (
+	HistoryRequest_Command_name = map[int32]string{
+		0: "LIST",
+		1: "EXPORT",
+		2: "IMPORT",
+		3: "FORGET",
+		4: "PRUNE",
+	}
+	HistoryRequest_Command_value = map[string]int32{
+		"LIST":   0,
+		"EXPORT": 1,
+		"IMPORT": 2,
+		"FORGET": 3,
+		"PRUNE":  4,
+	}
+)
+
+func (x HistoryRequest_Command) Enum() *HistoryRequest_Command {
+	p := new(HistoryRequest_Command)
+	*p = x
+	return p
+}
+
+func (x HistoryRequest_Command) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HistoryRequest_Command) Descriptor() protoreflect.EnumDescriptor {
+	return file_history_proto_enumTypes[0].Descriptor()
+}
+
+func (HistoryRequest_Command) Type() protoreflect.EnumType {
+	return &file_history_proto_enumTypes[0]
+}
+
+func (x HistoryRequest_Command) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HistoryRequest_Command.Descriptor instead.
+func (HistoryRequest_Command) EnumDescriptor() ([]byte, []int) {
+	return file_history_proto_rawDescGZIP(), []int{0, 0}
+}
+
+type HistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Command       HistoryRequest_Command `protobuf:"varint,1,opt,name=command,proto3,enum=pb.HistoryRequest_Command" json:"command,omitempty"`
+	Providers     []string               `protobuf:"bytes,2,rep,name=providers,proto3" json:"providers,omitempty"`
+	Query         string                 `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`
+	Identifier    string                 `protobuf:"bytes,4,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Days          uint32                 `protobuf:"varint,5,opt,name=days,proto3" json:"days,omitempty"`
+	Data          []byte                 `protobuf:"bytes,6,opt,name=data,proto3" json:"data,omitempty"`
+	Replace       bool                   `protobuf:"varint,7,opt,name=replace,proto3" json:"replace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryRequest) Reset() {
+	*x = HistoryRequest{}
+	mi := &file_history_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryRequest) ProtoMessage() {}
+
+func (x *HistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_history_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryRequest.ProtoReflect.Descriptor instead.
+func (*HistoryRequest) Descriptor() ([]byte, []int) {
+	return file_history_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HistoryRequest) GetCommand() HistoryRequest_Command {
+	if x != nil {
+		return x.Command
+	}
+	return HistoryRequest_LIST
+}
+
+func (x *HistoryRequest) GetProviders() []string {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+func (x *HistoryRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *HistoryRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *HistoryRequest) GetDays() uint32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+func (x *HistoryRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *HistoryRequest) GetReplace() bool {
+	if x != nil {
+		return x.Replace
+	}
+	return false
+}
+
+type HistoryResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Entries       []*HistoryResponse_Entry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	Data          []byte                   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Affected      int32                    `protobuf:"varint,3,opt,name=affected,proto3" json:"affected,omitempty"`
+	Skipped       []string                 `protobuf:"bytes,4,rep,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryResponse) Reset() {
+	*x = HistoryResponse{}
+	mi := &file_history_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryResponse) ProtoMessage() {}
+
+func (x *HistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_history_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryResponse.ProtoReflect.Descriptor instead.
+func (*HistoryResponse) Descriptor() ([]byte, []int) {
+	return file_history_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HistoryResponse) GetEntries() []*HistoryResponse_Entry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *HistoryResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *HistoryResponse) GetAffected() int32 {
+	if x != nil {
+		return x.Affected
+	}
+	return 0
+}
+
+func (x *HistoryResponse) GetSkipped() []string {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+type HistoryResponse_Entry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Query         string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	Identifier    string                 `protobuf:"bytes,3,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Amount        int32                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	LastUsed      int64                  `protobuf:"varint,5,opt,name=last_used,json=lastUsed,proto3" json:"last_used,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryResponse_Entry) Reset() {
+	*x = HistoryResponse_Entry{}
+	mi := &file_history_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryResponse_Entry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryResponse_Entry) ProtoMessage() {}
+
+func (x *HistoryResponse_Entry) ProtoReflect() protoreflect.Message {
+	mi := &file_history_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryResponse_Entry.ProtoReflect.Descriptor instead.
+func (*HistoryResponse_Entry) Descriptor() ([]byte, []int) {
+	return file_history_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *HistoryResponse_Entry) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *HistoryResponse_Entry) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *HistoryResponse_Entry) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *HistoryResponse_Entry) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *HistoryResponse_Entry) GetLastUsed() int64 {
+	if x != nil {
+		return x.LastUsed
+	}
+	return 0
+}
+
+var File_history_proto protoreflect.FileDescriptor
+
+const file_history_proto_rawDesc = "" +
+	"\n" +
+	"\rhistory.proto\x12\x02pb\"\xa0\x02\n" +
+	"\x0eHistoryRequest\x124\n" +
+	"\acommand\x18\x01 \x01(\x0e2\x1a.pb.HistoryRequest.CommandR\acommand\x12\x1c\n" +
+	"\tproviders\x18\x02 \x03(\tR\tproviders\x12\x14\n" +
+	"\x05query\x18\x03 \x01(\tR\x05query\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x04 \x01(\tR\n" +
+	"identifier\x12\x12\n" +
+	"\x04days\x18\x05 \x01(\rR\x04days\x12\x12\n" +
+	"\x04data\x18\x06 \x01(\fR\x04data\x12\x18\n" +
+	"\areplace\x18\a \x01(\bR\areplace\"B\n" +
+	"\aCommand\x12\b\n" +
+	"\x04LIST\x10\x00\x12\n" +
+	"\n" +
+	"\x06EXPORT\x10\x01\x12\n" +
+	"\n" +
+	"\x06IMPORT\x10\x02\x12\n" +
+	"\n" +
+	"\x06FORGET\x10\x03\x12\t\n" +
+	"\x05PRUNE\x10\x04\"\xa1\x02\n" +
+	"\x0fHistoryResponse\x123\n" +
+	"\aentries\x18\x01 \x03(\v2\x19.pb.HistoryResponse.EntryR\aentries\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\x12\x1a\n" +
+	"\baffected\x18\x03 \x01(\x05R\baffected\x12\x18\n" +
+	"\askipped\x18\x04 \x03(\tR\askipped\x1a\x8e\x01\n" +
+	"\x05Entry\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x03 \x01(\tR\n" +
+	"identifier\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x05R\x06amount\x12\x1b\n" +
+	"\tlast_used\x18\x05 \x01(\x03R\blastUsedB\x06Z\x04./pbb\x06proto3"
+
+var (
+	file_history_proto_rawDescOnce sync.Once
+	file_history_proto_rawDescData []byte
+)
+
+func file_history_proto_rawDescGZIP() []byte {
+	file_history_proto_rawDescOnce.Do(func() {
+		file_history_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_history_proto_rawDesc), len(file_history_proto_rawDesc)))
+	})
+	return file_history_proto_rawDescData
+}
+
+var file_history_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_history_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_history_proto_goTypes = []any{
+	(HistoryRequest_Command)(0),   // 0: pb.HistoryRequest.Command
+	(*HistoryRequest)(nil),        // 1: pb.HistoryRequest
+	(*HistoryResponse)(nil),       // 2: pb.HistoryResponse
+	(*HistoryResponse_Entry)(nil), // 3: pb.HistoryResponse.Entry
+}
+var file_history_proto_depIdxs = []int32{
+	0, // 0: pb.HistoryRequest.command:type_name -> pb.HistoryRequest.Command
+	3, // 1: pb.HistoryResponse.entries:type_name -> pb.HistoryResponse.Entry
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_history_proto_init() }
+func file_history_proto_init() {
+	if File_history_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_history_proto_rawDesc), len(file_history_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_history_proto_goTypes,
+		DependencyIndexes: file_history_proto_depIdxs,
+		EnumInfos:         file_history_proto_enumTypes,
+		MessageInfos:      file_history_proto_msgTypes,
+	}.Build()
+	File_history_proto = out.File
+	file_history_proto_goTypes = nil
+	file_history_proto_depIdxs = nil
+}
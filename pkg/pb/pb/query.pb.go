@@ -74,6 +74,9 @@ type QueryRequest struct {
 	Query         string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
 	Maxresults    int32                  `protobuf:"varint,3,opt,name=maxresults,proto3" json:"maxresults,omitempty"`
 	Exactsearch   bool                   `protobuf:"varint,4,opt,name=exactsearch,proto3" json:"exactsearch,omitempty"`
+	Stream        bool                   `protobuf:"varint,5,opt,name=stream,proto3" json:"stream,omitempty"`
+	Offset        int32                  `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
+	PageToken     string                 `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -136,11 +139,33 @@ func (x *QueryRequest) GetExactsearch() bool {
 	return false
 }
 
+func (x *QueryRequest) GetStream() bool {
+	if x != nil {
+		return x.Stream
+	}
+	return false
+}
+
+func (x *QueryRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *QueryRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
 type QueryResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Qid           int32                  `protobuf:"varint,1,opt,name=qid,proto3" json:"qid,omitempty"`
 	Iid           int32                  `protobuf:"varint,2,opt,name=iid,proto3" json:"iid,omitempty"`
 	Item          *QueryResponse_Item    `protobuf:"bytes,3,opt,name=item,proto3" json:"item,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,4,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -196,6 +221,157 @@ func (x *QueryResponse) GetItem() *QueryResponse_Item {
 	return nil
 }
 
+func (x *QueryResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type QueryBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Qid           int32                  `protobuf:"varint,1,opt,name=qid,proto3" json:"qid,omitempty"`
+	Iid           int32                  `protobuf:"varint,2,opt,name=iid,proto3" json:"iid,omitempty"`
+	Provider      string                 `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	Rank          int32                  `protobuf:"varint,4,opt,name=rank,proto3" json:"rank,omitempty"`
+	Items         []*QueryResponse_Item  `protobuf:"bytes,5,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryBatchResponse) Reset() {
+	*x = QueryBatchResponse{}
+	mi := &file_query_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryBatchResponse) ProtoMessage() {}
+
+func (x *QueryBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_query_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryBatchResponse.ProtoReflect.Descriptor instead.
+func (*QueryBatchResponse) Descriptor() ([]byte, []int) {
+	return file_query_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *QueryBatchResponse) GetQid() int32 {
+	if x != nil {
+		return x.Qid
+	}
+	return 0
+}
+
+func (x *QueryBatchResponse) GetIid() int32 {
+	if x != nil {
+		return x.Iid
+	}
+	return 0
+}
+
+func (x *QueryBatchResponse) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *QueryBatchResponse) GetRank() int32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *QueryBatchResponse) GetItems() []*QueryResponse_Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type QueryCommitResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Qid           int32                      `protobuf:"varint,1,opt,name=qid,proto3" json:"qid,omitempty"`
+	Iid           int32                      `protobuf:"varint,2,opt,name=iid,proto3" json:"iid,omitempty"`
+	Items         []*QueryCommitResponse_Ref `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	NextPageToken string                     `protobuf:"bytes,4,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryCommitResponse) Reset() {
+	*x = QueryCommitResponse{}
+	mi := &file_query_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryCommitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryCommitResponse) ProtoMessage() {}
+
+func (x *QueryCommitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_query_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryCommitResponse.ProtoReflect.Descriptor instead.
+func (*QueryCommitResponse) Descriptor() ([]byte, []int) {
+	return file_query_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *QueryCommitResponse) GetQid() int32 {
+	if x != nil {
+		return x.Qid
+	}
+	return 0
+}
+
+func (x *QueryCommitResponse) GetIid() int32 {
+	if x != nil {
+		return x.Iid
+	}
+	return 0
+}
+
+func (x *QueryCommitResponse) GetItems() []*QueryCommitResponse_Ref {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *QueryCommitResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 type QueryResponse_Item struct {
 	state         protoimpl.MessageState        `protogen:"open.v1"`
 	Identifier    string                        `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
@@ -208,13 +384,14 @@ type QueryResponse_Item struct {
 	Type          QueryResponse_Type            `protobuf:"varint,8,opt,name=type,proto3,enum=pb.QueryResponse_Type" json:"type,omitempty"`
 	Mimetype      string                        `protobuf:"bytes,9,opt,name=mimetype,proto3" json:"mimetype,omitempty"`
 	Preview       string                        `protobuf:"bytes,10,opt,name=preview,proto3" json:"preview,omitempty"`
+	Actions       []*QueryResponse_Item_Action  `protobuf:"bytes,11,rep,name=actions,proto3" json:"actions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *QueryResponse_Item) Reset() {
 	*x = QueryResponse_Item{}
-	mi := &file_query_proto_msgTypes[2]
+	mi := &file_query_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -226,7 +403,7 @@ func (x *QueryResponse_Item) String() string {
 func (*QueryResponse_Item) ProtoMessage() {}
 
 func (x *QueryResponse_Item) ProtoReflect() protoreflect.Message {
-	mi := &file_query_proto_msgTypes[2]
+	mi := &file_query_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -312,6 +489,13 @@ func (x *QueryResponse_Item) GetPreview() string {
 	return ""
 }
 
+func (x *QueryResponse_Item) GetActions() []*QueryResponse_Item_Action {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
 type QueryResponse_Item_FuzzyInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Start         int32                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
@@ -323,7 +507,7 @@ type QueryResponse_Item_FuzzyInfo struct {
 
 func (x *QueryResponse_Item_FuzzyInfo) Reset() {
 	*x = QueryResponse_Item_FuzzyInfo{}
-	mi := &file_query_proto_msgTypes[3]
+	mi := &file_query_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -335,7 +519,7 @@ func (x *QueryResponse_Item_FuzzyInfo) String() string {
 func (*QueryResponse_Item_FuzzyInfo) ProtoMessage() {}
 
 func (x *QueryResponse_Item_FuzzyInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_query_proto_msgTypes[3]
+	mi := &file_query_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -372,22 +556,155 @@ func (x *QueryResponse_Item_FuzzyInfo) GetPositions() []int32 {
 	return nil
 }
 
+type QueryResponse_Item_Action struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Name             string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Label            string                 `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	Icon             string                 `protobuf:"bytes,3,opt,name=icon,proto3" json:"icon,omitempty"`
+	Default          bool                   `protobuf:"varint,4,opt,name=default,proto3" json:"default,omitempty"`
+	RequiresArgument bool                   `protobuf:"varint,5,opt,name=requires_argument,json=requiresArgument,proto3" json:"requires_argument,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *QueryResponse_Item_Action) Reset() {
+	*x = QueryResponse_Item_Action{}
+	mi := &file_query_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryResponse_Item_Action) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryResponse_Item_Action) ProtoMessage() {}
+
+func (x *QueryResponse_Item_Action) ProtoReflect() protoreflect.Message {
+	mi := &file_query_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryResponse_Item_Action.ProtoReflect.Descriptor instead.
+func (*QueryResponse_Item_Action) Descriptor() ([]byte, []int) {
+	return file_query_proto_rawDescGZIP(), []int{1, 0, 1}
+}
+
+func (x *QueryResponse_Item_Action) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *QueryResponse_Item_Action) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *QueryResponse_Item_Action) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+func (x *QueryResponse_Item_Action) GetDefault() bool {
+	if x != nil {
+		return x.Default
+	}
+	return false
+}
+
+func (x *QueryResponse_Item_Action) GetRequiresArgument() bool {
+	if x != nil {
+		return x.RequiresArgument
+	}
+	return false
+}
+
+type QueryCommitResponse_Ref struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Identifier    string                 `protobuf:"bytes,2,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryCommitResponse_Ref) Reset() {
+	*x = QueryCommitResponse_Ref{}
+	mi := &file_query_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryCommitResponse_Ref) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryCommitResponse_Ref) ProtoMessage() {}
+
+func (x *QueryCommitResponse_Ref) ProtoReflect() protoreflect.Message {
+	mi := &file_query_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryCommitResponse_Ref.ProtoReflect.Descriptor instead.
+func (*QueryCommitResponse_Ref) Descriptor() ([]byte, []int) {
+	return file_query_proto_rawDescGZIP(), []int{3, 0}
+}
+
+func (x *QueryCommitResponse_Ref) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *QueryCommitResponse_Ref) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
 var File_query_proto protoreflect.FileDescriptor
 
 const file_query_proto_rawDesc = "" +
 	"\n" +
-	"\vquery.proto\x12\x02pb\"\x84\x01\n" +
+	"\vquery.proto\x12\x02pb\"\xd3\x01\n" +
 	"\fQueryRequest\x12\x1c\n" +
 	"\tproviders\x18\x01 \x03(\tR\tproviders\x12\x14\n" +
 	"\x05query\x18\x02 \x01(\tR\x05query\x12\x1e\n" +
 	"\n" +
 	"maxresults\x18\x03 \x01(\x05R\n" +
 	"maxresults\x12 \n" +
-	"\vexactsearch\x18\x04 \x01(\bR\vexactsearch\"\x94\x04\n" +
+	"\vexactsearch\x18\x04 \x01(\bR\vexactsearch\x12\x16\n" +
+	"\x06stream\x18\x05 \x01(\bR\x06stream\x12\x16\n" +
+	"\x06offset\x18\x06 \x01(\x05R\x06offset\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\a \x01(\tR\tpageToken\"\x85\x06\n" +
 	"\rQueryResponse\x12\x10\n" +
 	"\x03qid\x18\x01 \x01(\x05R\x03qid\x12\x10\n" +
 	"\x03iid\x18\x02 \x01(\x05R\x03iid\x12*\n" +
-	"\x04item\x18\x03 \x01(\v2\x16.pb.QueryResponse.ItemR\x04item\x1a\x93\x03\n" +
+	"\x04item\x18\x03 \x01(\v2\x16.pb.QueryResponse.ItemR\x04item\x12&\n" +
+	"\x0fnext_page_token\x18\x04 \x01(\tR\rnextPageToken\x1a\xdc\x04\n" +
 	"\x04Item\x12\x1e\n" +
 	"\n" +
 	"identifier\x18\x01 \x01(\tR\n" +
@@ -401,14 +718,37 @@ const file_query_proto_rawDesc = "" +
 	"\x04type\x18\b \x01(\x0e2\x16.pb.QueryResponse.TypeR\x04type\x12\x1a\n" +
 	"\bmimetype\x18\t \x01(\tR\bmimetype\x12\x18\n" +
 	"\apreview\x18\n" +
-	" \x01(\tR\apreview\x1aU\n" +
+	" \x01(\tR\apreview\x127\n" +
+	"\aactions\x18\v \x03(\v2\x1d.pb.QueryResponse.Item.ActionR\aactions\x1aU\n" +
 	"\tFuzzyInfo\x12\x14\n" +
 	"\x05start\x18\x01 \x01(\x05R\x05start\x12\x14\n" +
 	"\x05field\x18\x02 \x01(\tR\x05field\x12\x1c\n" +
-	"\tpositions\x18\x03 \x03(\x05R\tpositions\"\x1d\n" +
+	"\tpositions\x18\x03 \x03(\x05R\tpositions\x1a\x8d\x01\n" +
+	"\x06Action\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05label\x18\x02 \x01(\tR\x05label\x12\x12\n" +
+	"\x04icon\x18\x03 \x01(\tR\x04icon\x12\x18\n" +
+	"\adefault\x18\x04 \x01(\bR\adefault\x12+\n" +
+	"\x11requires_argument\x18\x05 \x01(\bR\x10requiresArgument\"\x1d\n" +
 	"\x04Type\x12\v\n" +
 	"\aREGULAR\x10\x00\x12\b\n" +
-	"\x04FILE\x10\x01B\x06Z\x04./pbb\x06proto3"
+	"\x04FILE\x10\x01\"\x96\x01\n" +
+	"\x12QueryBatchResponse\x12\x10\n" +
+	"\x03qid\x18\x01 \x01(\x05R\x03qid\x12\x10\n" +
+	"\x03iid\x18\x02 \x01(\x05R\x03iid\x12\x1a\n" +
+	"\bprovider\x18\x03 \x01(\tR\bprovider\x12\x12\n" +
+	"\x04rank\x18\x04 \x01(\x05R\x04rank\x12,\n" +
+	"\x05items\x18\x05 \x03(\v2\x16.pb.QueryResponse.ItemR\x05items\"\xd7\x01\n" +
+	"\x13QueryCommitResponse\x12\x10\n" +
+	"\x03qid\x18\x01 \x01(\x05R\x03qid\x12\x10\n" +
+	"\x03iid\x18\x02 \x01(\x05R\x03iid\x121\n" +
+	"\x05items\x18\x03 \x03(\v2\x1b.pb.QueryCommitResponse.RefR\x05items\x12&\n" +
+	"\x0fnext_page_token\x18\x04 \x01(\tR\rnextPageToken\x1aA\n" +
+	"\x03Ref\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x02 \x01(\tR\n" +
+	"identifierB\x06Z\x04./pbb\x06proto3"
 
 var (
 	file_query_proto_rawDescOnce sync.Once
@@ -424,24 +764,31 @@ func file_query_proto_rawDescGZIP() []byte {
 
 var (
 	file_query_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-	file_query_proto_msgTypes  = make([]protoimpl.MessageInfo, 4)
+	file_query_proto_msgTypes  = make([]protoimpl.MessageInfo, 8)
 	file_query_proto_goTypes   = []any{
 		(QueryResponse_Type)(0),              // 0: pb.QueryResponse.Type
 		(*QueryRequest)(nil),                 // 1: pb.QueryRequest
 		(*QueryResponse)(nil),                // 2: pb.QueryResponse
-		(*QueryResponse_Item)(nil),           // 3: pb.QueryResponse.Item
-		(*QueryResponse_Item_FuzzyInfo)(nil), // 4: pb.QueryResponse.Item.FuzzyInfo
+		(*QueryBatchResponse)(nil),           // 3: pb.QueryBatchResponse
+		(*QueryCommitResponse)(nil),          // 4: pb.QueryCommitResponse
+		(*QueryResponse_Item)(nil),           // 5: pb.QueryResponse.Item
+		(*QueryResponse_Item_FuzzyInfo)(nil), // 6: pb.QueryResponse.Item.FuzzyInfo
+		(*QueryResponse_Item_Action)(nil),    // 7: pb.QueryResponse.Item.Action
+		(*QueryCommitResponse_Ref)(nil),      // 8: pb.QueryCommitResponse.Ref
 	}
 )
 var file_query_proto_depIdxs = []int32{
-	3, // 0: pb.QueryResponse.item:type_name -> pb.QueryResponse.Item
-	4, // 1: pb.QueryResponse.Item.fuzzyinfo:type_name -> pb.QueryResponse.Item.FuzzyInfo
-	0, // 2: pb.QueryResponse.Item.type:type_name -> pb.QueryResponse.Type
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	5, // 0: pb.QueryResponse.item:type_name -> pb.QueryResponse.Item
+	5, // 1: pb.QueryBatchResponse.items:type_name -> pb.QueryResponse.Item
+	8, // 2: pb.QueryCommitResponse.items:type_name -> pb.QueryCommitResponse.Ref
+	6, // 3: pb.QueryResponse.Item.fuzzyinfo:type_name -> pb.QueryResponse.Item.FuzzyInfo
+	0, // 4: pb.QueryResponse.Item.type:type_name -> pb.QueryResponse.Type
+	7, // 5: pb.QueryResponse.Item.actions:type_name -> pb.QueryResponse.Item.Action
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_query_proto_init() }
@@ -455,7 +802,7 @@ func file_query_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_query_proto_rawDesc), len(file_query_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   4,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,10 @@
+package provider
+
+import "errors"
+
+// errors returned by Activate. These are mapped to error codes sent back to the client.
+var (
+	ErrUnknownIdentifier  = errors.New("unknown identifier")
+	ErrActionNotSupported = errors.New("action not supported")
+	ErrCommandFailed      = errors.New("command failed to start")
+)
@@ -0,0 +1,74 @@
+// Package provider defines the interface elephant providers have to implement.
+//
+// A provider plugin is built with `-buildmode=plugin` and has to export a single symbol named "Provider"
+// of type provider.Provider:
+//
+//	var Provider provider.Provider = &myProvider{}
+//
+// Optional functionality is detected by implementing the additional interfaces in this package.
+package provider
+
+import (
+	"context"
+
+	"github.com/abenz1267/elephant/pkg/pb/pb"
+)
+
+// APIVersion is the version of the provider interface. Providers reporting a different version are rejected.
+const APIVersion = 3
+
+// Symbol is the name of the symbol every plugin has to export.
+const Symbol = "Provider"
+
+type Provider interface {
+	// APIVersion has to return the APIVersion the provider was built against.
+	APIVersion() int
+	Name() string
+	NamePretty() string
+	Icon() string
+	PrintDoc()
+	// Setup is called once after the provider has been loaded. Load config and data here, not in init(). Providers
+	// returning an error are skipped, so never exit the process.
+	Setup() error
+	// Query has to return as soon as ctx is done, which happens when the query got superseded by a newer one or was cancelled.
+	// Subprocesses should be started with exec.CommandContext.
+	Query(ctx context.Context, qid uint32, iid uint32, query string, single bool, exact bool) []*pb.QueryResponse_Item
+	// Activate should return one of the errors in this package, optionally wrapped, so clients get a proper error code.
+	Activate(qid uint32, identifier, action string, arguments string) error
+	Cleanup(qid uint32)
+}
+
+// Actioner is implemented by providers supporting explicit actions.
+type Actioner interface {
+	// Actions returns all actions supported by Activate.
+	Actions() []string
+}
+
+// Previewer is implemented by providers that create previews lazily, only for items that are actually sent.
+type Previewer interface {
+	Preview(identifier string) string
+}
+
+// Subscriber is implemented by providers that notify subscribers about changes on their own.
+type Subscriber interface {
+	// Subscribe is called once after Setup. Call updated with the provider name, or a more specific value, whenever data changed.
+	Subscribe(updated func(value string))
+}
+
+// Configurable is implemented by providers with a config file, used to validate the users config.
+type Configurable interface {
+	// DefaultConfig returns a pointer to the providers config struct with all defaults set.
+	DefaultConfig() any
+}
+
+// Reloader is implemented by providers that can re-read their configuration at runtime.
+type Reloader interface {
+	// Reload should keep the current configuration if the new one can't be loaded.
+	Reload() error
+}
+
+// Shutdowner is implemented by providers that have to clean up before elephant exits.
+type Shutdowner interface {
+	// Shutdown is called once on exit. Stop watchers and child processes and flush pending data to disk.
+	Shutdown()
+}